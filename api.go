@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// API represents Slack Web API client, complementing slack-go with response fields it does not expose
+type API struct {
+	*slack.Client
+
+	token string
+	url   string
+	http  *http.Client
+}
+
+// scheduleResponse represents a response of 'chat.scheduleMessage'
+type scheduleResponse struct {
+	slack.SlackResponse
+	Channel            string `json:"channel"`
+	ScheduledMessageID string `json:"scheduled_message_id"`
+}
+
+// NewAPI returns Slack Web API client of a token, calling an API at a given URL
+func NewAPI(token, apiURL string) *API {
+	client := &http.Client{}
+
+	return &API{
+		Client: slack.New(token, slack.OptionAPIURL(apiURL), slack.OptionHTTPClient(client)),
+		token:  token,
+		url:    apiURL,
+		http:   client,
+	}
+}
+
+// ScheduleMessageContext schedules a message and returns a channel and an ID of a scheduled message.
+// slack-go does not expose 'scheduled_message_id' of a response, so the request is sent directly.
+func (a *API) ScheduleMessageContext(ctx context.Context, channelID, postAt string, options ...slack.MsgOption) (string, string, error) {
+	endpoint, values, err := slack.UnsafeApplyMsgOptions(a.token, channelID, a.url, append(options, slack.MsgOptionSchedule(postAt))...)
+	if err != nil {
+		return "", "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return "", "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	// same errors as slack-go, so they are retried alike
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retry, _ := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64)
+		return "", "", &slack.RateLimitedError{RetryAfter: time.Duration(retry) * time.Second}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", slack.StatusCodeError{Code: resp.StatusCode, Status: resp.Status}
+	}
+
+	response := new(scheduleResponse)
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return "", "", errors.Wrap(err, "invalid response")
+	}
+
+	if err := response.Err(); err != nil {
+		return "", "", err
+	}
+
+	channel := response.Channel
+	if channel == "" {
+		channel = channelID
+	}
+
+	return channel, response.ScheduledMessageID, nil
+}
@@ -0,0 +1,90 @@
+package main_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIScheduleMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		StatusCode      int
+		Response        string
+		ExpectedChannel string
+		ExpectedID      string
+		ExpectedError   error
+	}
+
+	suite := map[string]test{
+		"Scheduled": {
+			StatusCode:      http.StatusOK,
+			Response:        `{"ok": true, "channel": "C123", "scheduled_message_id": "Q2", "post_at": "4070944800"}`,
+			ExpectedChannel: "C123",
+			ExpectedID:      "Q2",
+			ExpectedError:   nil,
+		},
+		"Slack Error": {
+			StatusCode:      http.StatusOK,
+			Response:        `{"ok": false, "error": "time_in_past"}`,
+			ExpectedChannel: "",
+			ExpectedID:      "",
+			ExpectedError:   slack.SlackErrorResponse{Err: "time_in_past"},
+		},
+		"Rate Limited": {
+			StatusCode:      http.StatusTooManyRequests,
+			Response:        "",
+			ExpectedChannel: "",
+			ExpectedID:      "",
+			ExpectedError:   &slack.RateLimitedError{RetryAfter: 3 * time.Second},
+		},
+		"Server Error": {
+			StatusCode:      http.StatusServiceUnavailable,
+			Response:        "",
+			ExpectedChannel: "",
+			ExpectedID:      "",
+			ExpectedError:   slack.StatusCodeError{Code: http.StatusServiceUnavailable, Status: "503 Service Unavailable"},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		var calls int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+
+			assert.Equal("/chat.scheduleMessage", r.URL.Path)
+			assert.Equal("4070944800", r.FormValue("post_at"))
+			assert.Equal("self", r.FormValue("channel"))
+			assert.Equal("token", r.FormValue("token"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(test.StatusCode)
+			fmt.Fprint(w, test.Response)
+		}))
+
+		cli := app.NewAPI("token", server.URL+"/")
+
+		channel, id, err := cli.ScheduleMessageContext(context.Background(), "self", "4070944800", slack.MsgOptionText("text", false))
+
+		assert.Equal(test.ExpectedError, err)
+		assert.Equal(test.ExpectedChannel, channel)
+		assert.Equal(test.ExpectedID, id)
+		assert.Equal(1, calls)
+
+		server.Close()
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"action-notify-slack/internal/actions"
+
 	"github.com/pkg/errors"
 	"github.com/slack-go/slack"
 )
@@ -17,67 +19,107 @@ import (
 type Client interface {
 	PostMessageContext(context.Context, string, ...slack.MsgOption) (string, string, error)
 	UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error)
+	UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+	DeleteMessageContext(ctx context.Context, channel, messageTimestamp string) (string, string, error)
+	ScheduleMessageContext(ctx context.Context, channelID, postAt string, options ...slack.MsgOption) (string, string, error)
+	DeleteScheduledMessageContext(ctx context.Context, params *slack.DeleteScheduledMessageParameters) (bool, error)
+	PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error)
+	GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error)
+	GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error)
+	GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
 }
 
 // Slack represents app config
 type Slack struct {
-	Channel   string
-	Context   context.Context
-	Timestamp string
+	Channel         string
+	Context         context.Context
+	Timestamp       string
+	ThreadTimestamp string
+	Broadcast       bool
+	BlockKit        bool
+	Theme           *Theme
+	Retry           RetryPolicy
+	PostAt          time.Time
+	EphemeralUser   string
+	ActorSlackID    string
+	StartedAt       time.Time
+	History         []StatusChange
+	Stages          []Stage
 }
 
-// GetTemplate returns default Slack Message Template
-func GetTemplate(status string, failed bool, additions []slack.AttachmentField) slack.Attachment {
-	var color string
-	var failureColor string = "#fd0000"
-
-	if strings.ToLower(status) == "running" || strings.ToLower(status) == "started" || strings.ToLower(status) == "building" || strings.ToLower(status) == "initializing" {
-		color = "#fbf000"
-	} else if strings.ToLower(status) == "deploying" || strings.ToLower(status) == "uploading" || strings.ToLower(status) == "publishing" || strings.ToLower(status) == "creating" {
-		color = "#fda100"
-	} else if strings.ToLower(status) == "finished" || strings.ToLower(status) == "succeeded" || strings.ToLower(status) == "passed" || strings.ToLower(status) == "built" || strings.ToLower(status) == "released" {
-		color = "#0ce823"
-	} else if strings.ToLower(status) == "failed" || strings.ToLower(status) == "aborted" || strings.ToLower(status) == "canceled" || strings.ToLower(status) == "terminated" {
-		color = failureColor
-	} else {
-		color = "#777777"
-	}
+// GetFields returns fields of default Slack Message Template
+func GetFields(data *TemplateData) []slack.AttachmentField {
+	initiator := fmt.Sprintf("<%s/%s|%s>", data.ServerURL, data.Actor, data.Actor)
 
-	s := status
-	if failed {
-		color = failureColor
-		s = "failed"
+	// mention an initiator of a failed run, so they get notified
+	if data.ActorSlackID != "" && data.Style.Failure {
+		initiator = fmt.Sprintf("<@%s>", data.ActorSlackID)
 	}
 
-	fields := []slack.AttachmentField{
+	return []slack.AttachmentField{
 		{
 			Title: "Repository",
-			Value: fmt.Sprintf("<https://github.com/%s|%s>", os.Getenv("GITHUB_REPOSITORY"), strings.Split(os.Getenv("GITHUB_REPOSITORY"), "/")[1]),
+			Value: fmt.Sprintf("<%s/%s|%s>", data.ServerURL, data.Repository, strings.Split(data.Repository, "/")[1]),
 			Short: true,
 		},
 		{
 			Title: "Workflow",
-			Value: fmt.Sprintf("<https://github.com/%s/actions?query=workflow", os.Getenv("GITHUB_REPOSITORY")) + "%3A" + fmt.Sprintf("%s|%s>", os.Getenv("GITHUB_WORKFLOW"), os.Getenv("GITHUB_WORKFLOW")),
+			Value: fmt.Sprintf("<%s/%s/actions?query=workflow", data.ServerURL, data.Repository) + "%3A" + fmt.Sprintf("%s|%s>", data.Workflow, data.Workflow),
 			Short: true,
 		},
 		{
 			Title: "Initiator",
-			Value: fmt.Sprintf("<https://github.com/%s|%s>", os.Getenv("GITHUB_ACTOR"), os.Getenv("GITHUB_ACTOR")),
+			Value: initiator,
 			Short: true,
 		},
 		{
 			Title: "Status",
-			Value: fmt.Sprintf("<https://github.com/%s/actions/runs/%s|%s>", os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"), strings.ToUpper(s)),
+			Value: fmt.Sprintf("<%s/%s/actions/runs/%s|%s>", data.ServerURL, data.Repository, data.RunID, data.Label()),
 			Short: true,
 		},
 	}
+}
+
+// GetResultFields returns a field per job or step result, and a summary of results
+func GetResultFields(data *TemplateData) []slack.AttachmentField {
+	fields := make([]slack.AttachmentField, 0)
+
+	if len(data.Results) == 0 {
+		return fields
+	}
+
+	for _, r := range data.Results {
+		label := r.Style.Label
+		if label == "" {
+			label = strings.ToUpper(r.Result)
+		}
+
+		fields = append(fields, slack.AttachmentField{
+			Title: r.Name,
+			Value: strings.TrimSpace(fmt.Sprintf("%s %s", r.Style.Emoji, label)),
+			Short: true,
+		})
+	}
+
+	return append(fields, slack.AttachmentField{
+		Title: "Summary",
+		Value: data.Counts().String(),
+		Short: false,
+	})
+}
+
+// GetTemplate returns default Slack Message Template
+func GetTemplate(data *TemplateData) slack.Attachment {
+	fields := append(GetFields(data), GetEventFields(data)...)
+	fields = append(fields, GetResultFields(data)...)
 
-	if len(additions) > 0 {
-		fields = append(fields, additions...)
+	if len(data.Fields) > 0 {
+		fields = append(fields, data.Fields...)
 	}
 
 	msg := slack.Attachment{
-		Color:      color,
+		Color:      data.Style.Color,
 		Fields:     fields,
 		Footer:     "<https://github.com/ReasonSoftware/action-notify-slack|ReasonSoftware/action-notify-slack>",
 		FooterIcon: "https://cdn.reasonsecurity.com/images/logo.png",
@@ -89,18 +131,46 @@ func GetTemplate(status string, failed bool, additions []slack.AttachmentField)
 
 // SendTemplate sends a template message
 func (s *Slack) SendTemplate(cli Client, fields []slack.AttachmentField) (string, error) {
-	failure := false
+	fields, err := s.withRunFields(fields)
+	if err != nil {
+		return "", err
+	}
 
-	if os.Getenv("FAIL") != "" {
-		var err error
-		failure, err = strconv.ParseBool(os.Getenv("FAIL"))
-		if err != nil {
-			return "", errors.Wrap(err, "error parsing env.var 'FAIL'")
-		}
+	data, err := s.GetTemplateData(fields)
+	if err != nil {
+		return "", err
+	}
+
+	if s.BlockKit {
+		return s.send(cli, slack.MsgOptionCompose(slack.MsgOptionBlocks(GetBlocks(data)...), slack.MsgOptionText(GetFallbackText(data), false)))
+	}
+
+	return s.send(cli, slack.MsgOptionAttachments(GetTemplate(data)))
+}
+
+// SendTemplateFile sends a message rendered from a user provided Go template file
+func (s *Slack) SendTemplateFile(cli Client, filename string, fields []slack.AttachmentField) (string, error) {
+	fields, err := s.withRunFields(fields)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := s.GetTemplateData(fields)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := RenderTemplate(filename, data)
+	if err != nil {
+		return "", err
+	}
+
+	msg, err := ParsePayload(file, nil)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("invalid JSON rendered from template '%s'", filename))
 	}
 
-	t := GetTemplate(os.Getenv("STATUS"), failure, fields)
-	return s.send(cli, slack.MsgOptionAttachments(t))
+	return s.send(cli, msg)
 }
 
 // SendAttachmentFromFile sends an attachment provided via json file
@@ -110,28 +180,164 @@ func (s *Slack) SendAttachmentFromFile(cli Client, filename string, fields []sla
 		return "", errors.Wrap(err, fmt.Sprintf("error reading file '%s'", filename))
 	}
 
-	var slice []slack.Attachment
-	if err := json.Unmarshal(file, &slice); err != nil {
-		var single slack.Attachment
+	fields, err = s.withRunFields(fields)
+	if err != nil {
+		return "", err
+	}
 
-		if err := json.Unmarshal(file, &single); err != nil {
-			return "", errors.Wrap(err, fmt.Sprintf("invalid JSON file '%s'", filename))
-		}
+	// an attachment may contain its own 'blocks', hence a file is never parsed as a whole message
+	msg, err := ParseAttachments(file, fields)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("invalid JSON file '%s'", filename))
+	}
+
+	return s.send(cli, msg)
+}
+
+// GetTemplateData returns a data model of the current run
+func (s *Slack) GetTemplateData(fields []slack.AttachmentField) (*TemplateData, error) {
+	failure, err := GetFailure()
+	if err != nil {
+		return nil, err
+	}
 
-		single.Fields = append(single.Fields, fields...)
-		return s.send(cli, slack.MsgOptionAttachments(single))
+	status, results, err := GetStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Style = s.GetTheme().Style(results[i].Result, false)
 	}
 
-	for _, a := range slice {
-		a.Fields = append(a.Fields, fields...)
+	// event context is optional, a broken event payload should not fail a notification
+	event, err := LoadEvent(os.Getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		actions.Warning(err.Error())
+	}
+
+	data := NewTemplateData(s.GetTheme(), status, failure, fields)
+	data.Results = results
+	data.Event = event
+	data.ActorSlackID = s.ActorSlackID
+	data.StartedAt = s.StartedAt
+
+	// an appearance of a card follows its stages, while a text of a status is kept as is
+	if len(s.Stages) > 0 && !failure {
+		style := s.GetTheme().Style(GetStagesStatus(s.Stages), false)
+		data.Style.Color = style.Color
+		data.Style.Emoji = style.Emoji
+		data.Style.Failure = style.Failure
 	}
 
-	return s.send(cli, slack.MsgOptionAttachments(slice...))
+	return data, nil
+}
+
+// GetTheme returns a theme of the messages
+func (s *Slack) GetTheme() *Theme {
+	if s.Theme == nil {
+		return DefaultTheme
+	}
+
+	return s.Theme
+}
+
+// GetFailure returns a parsed value of a failure trap
+func GetFailure() (bool, error) {
+	if os.Getenv("FAIL") == "" {
+		return false, nil
+	}
+
+	failure, err := strconv.ParseBool(os.Getenv("FAIL"))
+	if err != nil {
+		return false, errors.Wrap(err, "error parsing env.var 'FAIL'")
+	}
+
+	return failure, nil
+}
+
+// Delete deletes a message, considering an already deleted message as a success
+func (s *Slack) Delete(cli Client) error {
+	err := s.Retry.Do(s.Context, func() error {
+		_, _, err := cli.DeleteMessageContext(s.Context, s.Channel, s.Timestamp)
+		return err
+	})
+
+	if IsSlackError(err, "message_not_found") {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "error deleting message")
+	}
+
+	return nil
+}
+
+// GetPermalink returns a permalink of a message
+func (s *Slack) GetPermalink(cli Client, ts string) (string, error) {
+	var link string
+	err := s.Retry.Do(s.Context, func() error {
+		var err error
+		link, err = cli.GetPermalinkContext(s.Context, &slack.PermalinkParameters{Channel: s.Channel, Ts: ts})
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "error retrieving message permalink")
+	}
+
+	return link, nil
 }
 
 func (s *Slack) send(cli Client, options ...slack.MsgOption) (string, error) {
+	if !s.PostAt.IsZero() {
+		return s.schedule(cli, options...)
+	}
+
+	if s.EphemeralUser != "" {
+		return s.sendEphemeral(cli, options...)
+	}
+
+	if s.ThreadTimestamp != "" {
+		options = append(options, slack.MsgOptionTS(s.ThreadTimestamp))
+
+		failure, err := GetFailure()
+		if err != nil {
+			return "", err
+		}
+
+		status, _, err := GetStatus()
+		if err != nil {
+			return "", err
+		}
+
+		if s.Broadcast && s.GetTheme().Style(status, failure).Failure {
+			options = append(options, slack.MsgOptionBroadcast())
+		}
+
+		var ts string
+		err = s.Retry.Do(s.Context, func() error {
+			var err error
+			_, ts, err = cli.PostMessageContext(s.Context, s.Channel, options...)
+			return err
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "error sending thread reply")
+		}
+
+		return ts, nil
+	}
+
+	// a message of a run carries its metadata, so any later job is able to find and update it
+	options = []slack.MsgOption{slack.MsgOptionCompose(append(options, slack.MsgOptionMetadata(GetMetadata()))...)}
+
 	if s.Timestamp != "" {
-		_, ts, _, err := cli.UpdateMessageContext(s.Context, s.Channel, s.Timestamp, options...)
+		var ts string
+		err := s.Retry.Do(s.Context, func() error {
+			var err error
+			_, ts, _, err = cli.UpdateMessageContext(s.Context, s.Channel, s.Timestamp, options...)
+			return err
+		})
 		if err != nil {
 			return "", errors.Wrap(err, "error updating message")
 		}
@@ -139,10 +345,36 @@ func (s *Slack) send(cli Client, options ...slack.MsgOption) (string, error) {
 		return ts, nil
 	}
 
-	_, ts, err := cli.PostMessageContext(s.Context, s.Channel, options...)
+	var ts string
+	err := s.Retry.Do(s.Context, func() error {
+		var err error
+		_, ts, err = cli.PostMessageContext(s.Context, s.Channel, options...)
+		return err
+	})
 	if err != nil {
 		return "", errors.Wrap(err, "error sending message")
 	}
 
 	return ts, nil
 }
+
+// sendEphemeral sends a message visible to a single user only. Such messages can not be updated.
+func (s *Slack) sendEphemeral(cli Client, options ...slack.MsgOption) (string, error) {
+	if s.ThreadTimestamp != "" {
+		options = append(options, slack.MsgOptionTS(s.ThreadTimestamp))
+	} else if s.Timestamp != "" {
+		return "", errors.New("ephemeral messages can not be updated")
+	}
+
+	var ts string
+	err := s.Retry.Do(s.Context, func() error {
+		var err error
+		ts, err = cli.PostEphemeralContext(s.Context, s.Channel, s.EphemeralUser, options...)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "error sending ephemeral message")
+	}
+
+	return ts, nil
+}
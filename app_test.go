@@ -1,14 +1,17 @@
 package main_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	app "action-notify-slack"
 
+	"action-notify-slack/internal/actions"
 	"action-notify-slack/mocks"
 
 	"github.com/pkg/errors"
@@ -405,6 +408,36 @@ func TestSendTemplate(t *testing.T) {
 			MockError:      nil,
 			ExpectedError:  "",
 		},
+		"Block Kit": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: "",
+				BlockKit:  true,
+			},
+			Parameter1:     []slack.AttachmentField{},
+			ExpectedOutput: fmt.Sprint(time.Now().Unix()),
+			MockError:      nil,
+			ExpectedError:  "",
+		},
+		"Block Kit Update with Fields": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: fmt.Sprint(time.Now().Unix()),
+				BlockKit:  true,
+			},
+			Parameter1: []slack.AttachmentField{
+				{
+					Title: "key-1",
+					Value: "value-1",
+					Short: true,
+				},
+			},
+			ExpectedOutput: fmt.Sprint(time.Now().Unix()),
+			MockError:      nil,
+			ExpectedError:  "",
+		},
 		"slack.PostMessageContext Error": {
 			Receiver: &app.Slack{
 				Channel:   "self",
@@ -450,6 +483,447 @@ func TestSendTemplate(t *testing.T) {
 	}
 }
 
+func TestSendTemplateThread(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Receiver        *app.Slack
+		Status          string
+		Fail            string
+		ExpectedOptions int
+		ExpectedOutput  string
+		MockError       error
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"Reply": {
+			Receiver: &app.Slack{
+				Channel:         "self",
+				Context:         context.Background(),
+				Timestamp:       "",
+				ThreadTimestamp: "1589146397.007200",
+				Broadcast:       false,
+			},
+			Status:          "running",
+			Fail:            "",
+			ExpectedOptions: 2,
+			ExpectedOutput:  fmt.Sprint(time.Now().Unix()),
+			MockError:       nil,
+			ExpectedError:   "",
+		},
+		"Reply Instead of Update": {
+			Receiver: &app.Slack{
+				Channel:         "self",
+				Context:         context.Background(),
+				Timestamp:       "1589146397.007200",
+				ThreadTimestamp: "1589146397.007200",
+				Broadcast:       true,
+			},
+			Status:          "finished",
+			Fail:            "false",
+			ExpectedOptions: 2,
+			ExpectedOutput:  fmt.Sprint(time.Now().Unix()),
+			MockError:       nil,
+			ExpectedError:   "",
+		},
+		"Broadcast Failed Status": {
+			Receiver: &app.Slack{
+				Channel:         "self",
+				Context:         context.Background(),
+				Timestamp:       "",
+				ThreadTimestamp: "1589146397.007200",
+				Broadcast:       true,
+			},
+			Status:          "aborted",
+			Fail:            "",
+			ExpectedOptions: 3,
+			ExpectedOutput:  fmt.Sprint(time.Now().Unix()),
+			MockError:       nil,
+			ExpectedError:   "",
+		},
+		"Broadcast Failure Trap": {
+			Receiver: &app.Slack{
+				Channel:         "self",
+				Context:         context.Background(),
+				Timestamp:       "",
+				ThreadTimestamp: "1589146397.007200",
+				Broadcast:       true,
+			},
+			Status:          "finished",
+			Fail:            "true",
+			ExpectedOptions: 3,
+			ExpectedOutput:  fmt.Sprint(time.Now().Unix()),
+			MockError:       nil,
+			ExpectedError:   "",
+		},
+		"slack.PostMessageContext Error": {
+			Receiver: &app.Slack{
+				Channel:         "self",
+				Context:         context.Background(),
+				Timestamp:       "",
+				ThreadTimestamp: "1589146397.007200",
+				Broadcast:       false,
+			},
+			Status:          "running",
+			Fail:            "",
+			ExpectedOptions: 2,
+			ExpectedOutput:  "",
+			MockError:       errors.New("reason"),
+			ExpectedError:   "error sending thread reply: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		os.Setenv("STATUS", test.Status)
+		os.Setenv("FAIL", test.Fail)
+
+		args := []interface{}{test.Receiver.Context, test.Receiver.Channel}
+		for i := 0; i < test.ExpectedOptions; i++ {
+			args = append(args, mock.AnythingOfType("slack.MsgOption"))
+		}
+
+		m := new(mocks.Client)
+		m.On("PostMessageContext", args...).Return("", test.ExpectedOutput, test.MockError)
+
+		result, err := test.Receiver.SendTemplate(m, []slack.AttachmentField{})
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+		m.AssertNotCalled(t, "UpdateMessageContext")
+	}
+
+	os.Setenv("STATUS", "running")
+	os.Unsetenv("FAIL")
+}
+
+func TestGetPermalink(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Receiver       *app.Slack
+		Timestamp      string
+		ExpectedOutput string
+		MockError      error
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Permalink": {
+			Receiver: &app.Slack{
+				Channel: "self",
+				Context: context.Background(),
+			},
+			Timestamp:      "1589146397.007200",
+			ExpectedOutput: "https://workspace.slack.com/archives/self/p1589146397007200",
+			MockError:      nil,
+			ExpectedError:  "",
+		},
+		"slack.GetPermalinkContext Error": {
+			Receiver: &app.Slack{
+				Channel: "self",
+				Context: context.Background(),
+			},
+			Timestamp:      "1589146397.007200",
+			ExpectedOutput: "",
+			MockError:      errors.New("reason"),
+			ExpectedError:  "error retrieving message permalink: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m := new(mocks.Client)
+		m.On("GetPermalinkContext", test.Receiver.Context, &slack.PermalinkParameters{Channel: test.Receiver.Channel, Ts: test.Timestamp}).Return(test.ExpectedOutput, test.MockError)
+
+		result, err := test.Receiver.GetPermalink(m, test.Timestamp)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+	}
+}
+
+func TestSendTemplateEphemeral(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Receiver       *app.Slack
+		ExpectedOutput string
+		MockError      error
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Ephemeral": {
+			Receiver: &app.Slack{
+				Channel:       "self",
+				Context:       context.Background(),
+				EphemeralUser: "U123",
+			},
+			ExpectedOutput: "1589146397.007200",
+			MockError:      nil,
+			ExpectedError:  "",
+		},
+		"Ephemeral Thread Reply": {
+			Receiver: &app.Slack{
+				Channel:         "self",
+				Context:         context.Background(),
+				Timestamp:       "1589146397.007200",
+				ThreadTimestamp: "1589146397.007200",
+				EphemeralUser:   "U123",
+			},
+			ExpectedOutput: "1589146398.007200",
+			MockError:      nil,
+			ExpectedError:  "",
+		},
+		"Ephemeral Update": {
+			Receiver: &app.Slack{
+				Channel:       "self",
+				Context:       context.Background(),
+				Timestamp:     "1589146397.007200",
+				EphemeralUser: "U123",
+			},
+			ExpectedOutput: "",
+			MockError:      nil,
+			ExpectedError:  "ephemeral messages can not be updated",
+		},
+		"slack.PostEphemeralContext Error": {
+			Receiver: &app.Slack{
+				Channel:       "self",
+				Context:       context.Background(),
+				EphemeralUser: "U123",
+			},
+			ExpectedOutput: "",
+			MockError:      errors.New("reason"),
+			ExpectedError:  "error sending ephemeral message: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m := new(mocks.Client)
+		m.On("PostEphemeralContext", test.Receiver.Context, test.Receiver.Channel, test.Receiver.EphemeralUser, mock.AnythingOfType("slack.MsgOption")).Return(test.ExpectedOutput, test.MockError)
+		m.On("PostEphemeralContext", test.Receiver.Context, test.Receiver.Channel, test.Receiver.EphemeralUser, mock.AnythingOfType("slack.MsgOption"), mock.AnythingOfType("slack.MsgOption")).Return(test.ExpectedOutput, test.MockError)
+
+		result, err := test.Receiver.SendTemplate(m, []slack.AttachmentField{})
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+		m.AssertNotCalled(t, "UpdateMessageContext")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Receiver      *app.Slack
+		MockError     error
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Delete": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: "1589146397.007200",
+			},
+			MockError:     nil,
+			ExpectedError: "",
+		},
+		"Already Deleted": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: "1589146397.007200",
+			},
+			MockError:     slack.SlackErrorResponse{Err: "message_not_found"},
+			ExpectedError: "",
+		},
+		"slack.DeleteMessageContext Error": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: "1589146397.007200",
+			},
+			MockError:     errors.New("reason"),
+			ExpectedError: "error deleting message: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m := new(mocks.Client)
+		m.On("DeleteMessageContext", test.Receiver.Context, test.Receiver.Channel, test.Receiver.Timestamp).Return(test.Receiver.Channel, test.Receiver.Timestamp, test.MockError)
+
+		err := test.Receiver.Delete(m)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+	}
+}
+
+func TestGetTemplateResults(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Status         string
+		ExpectedColor  string
+		ExpectedFields []slack.AttachmentField
+	}
+
+	suite := map[string]test{
+		"Plain Status": {
+			Status:         "running",
+			ExpectedColor:  "#fbf000",
+			ExpectedFields: []slack.AttachmentField{},
+		},
+		"Needs": {
+			Status:        `{"build": {"result": "success"}, "deploy": {"result": "skipped"}, "test": {"result": "failure"}}`,
+			ExpectedColor: "#fd0000",
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "build", Value: ":green_square: SUCCEEDED", Short: true},
+				{Title: "deploy", Value: ":fast_forward: SKIPPED", Short: true},
+				{Title: "test", Value: ":red_square: FAILED", Short: true},
+				{Title: "Summary", Value: "1 succeeded, 1 failed, 1 skipped", Short: false},
+			},
+		},
+		"Needs Succeeded": {
+			Status:        `{"build": {"result": "success"}, "test": {"result": "success"}}`,
+			ExpectedColor: "#0ce823",
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "build", Value: ":green_square: SUCCEEDED", Short: true},
+				{Title: "test", Value: ":green_square: SUCCEEDED", Short: true},
+				{Title: "Summary", Value: "2 succeeded, 0 failed, 0 skipped", Short: false},
+			},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		os.Setenv("STATUS", test.Status)
+
+		s := &app.Slack{Channel: "self", Context: context.Background()}
+
+		data, err := s.GetTemplateData([]slack.AttachmentField{})
+		assert.Equal(nil, err)
+
+		attachment := app.GetTemplate(data)
+
+		assert.Equal(test.ExpectedColor, attachment.Color)
+		assert.Equal(test.ExpectedFields, attachment.Fields[4:])
+	}
+
+	os.Setenv("STATUS", "running")
+}
+
+func TestGetTemplateDataBrokenEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary directory")
+	defer os.RemoveAll(dir)
+
+	malformed := filepath.Join(dir, "event.json")
+	err = os.WriteFile(malformed, []byte(`{"ref": `), 0644)
+	assert.Equal(nil, err, "preparation: error writing event file")
+
+	suite := map[string]struct {
+		Path            string
+		ExpectedWarning string
+	}{
+		"Malformed Event": {Path: malformed, ExpectedWarning: fmt.Sprintf("::warning::invalid event payload '%s'", malformed)},
+		"Missing Event":   {Path: filepath.Join(dir, "missing.json"), ExpectedWarning: fmt.Sprintf("::warning::error reading event payload '%s'", filepath.Join(dir, "missing.json"))},
+	}
+
+	var out bytes.Buffer
+	actions.Stdout = &out
+	defer func() { actions.Stdout = os.Stdout }()
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		out.Reset()
+		os.Setenv("GITHUB_EVENT_PATH", test.Path)
+
+		s := &app.Slack{Channel: "self", Context: context.Background()}
+
+		data, err := s.GetTemplateData([]slack.AttachmentField{})
+
+		assert.Equal(nil, err)
+		assert.Nil(data.Event)
+		assert.Contains(out.String(), test.ExpectedWarning)
+	}
+
+	os.Unsetenv("GITHUB_EVENT_PATH")
+}
+
+func TestGetFields(t *testing.T) {
+	assert := assert.New(t)
+
+	data := &app.TemplateData{
+		ServerURL:  "https://example.com/github",
+		Repository: "org/proj",
+		Workflow:   "ci",
+		Actor:      "user",
+		RunID:      "1",
+		Status:     "running",
+	}
+
+	expected := []slack.AttachmentField{
+		{Title: "Repository", Value: "<https://example.com/github/org/proj|proj>", Short: true},
+		{Title: "Workflow", Value: "<https://example.com/github/org/proj/actions?query=workflow%3Aci|ci>", Short: true},
+		{Title: "Initiator", Value: "<https://example.com/github/user|user>", Short: true},
+		{Title: "Status", Value: "<https://example.com/github/org/proj/actions/runs/1|RUNNING>", Short: true},
+	}
+
+	assert.Equal(expected, app.GetFields(data))
+
+	// initiator of a failed run is mentioned
+	data.ActorSlackID = "U123"
+	assert.Equal(expected[2].Value, app.GetFields(data)[2].Value)
+
+	data.Style = app.DefaultTheme.Style("failure", false)
+	assert.Equal("<@U123>", app.GetFields(data)[2].Value)
+}
+
 func TestMain(m *testing.M) {
 	os.Setenv("GITHUB_ACTOR", "username")
 	os.Setenv("GITHUB_REPOSITORY", "ore/proj")
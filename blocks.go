@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// maxSectionFields is a limit of fields Slack accepts in a single section block
+const maxSectionFields = 10
+
+// GetBlocks returns default Slack Message Template rendered as Block Kit blocks
+func GetBlocks(data *TemplateData) []slack.Block {
+	header := slack.NewHeaderBlock(
+		slack.NewTextBlockObject(slack.PlainTextType, fmt.Sprintf("%s %s: %s", data.Style.Emoji, strings.Split(data.Repository, "/")[1], data.Label()), true, false),
+	)
+
+	blocks := []slack.Block{header}
+	blocks = append(blocks, GetSectionBlocks(GetFields(data))...)
+	blocks = append(blocks, GetSectionBlocks(GetEventFields(data))...)
+	blocks = append(blocks, GetSectionBlocks(GetResultFields(data))...)
+	blocks = append(blocks, GetSectionBlocks(data.Fields)...)
+
+	footer := slack.NewContextBlock(
+		"",
+		slack.NewImageBlockElement("https://cdn.reasonsecurity.com/images/logo.png", "ReasonSoftware"),
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("<https://github.com/ReasonSoftware/action-notify-slack|ReasonSoftware/action-notify-slack> | <!date^%v^{date_short_pretty} {time}|%s>", time.Now().Unix(), time.Now().UTC().Format(time.RFC1123)), false, false),
+	)
+
+	return append(blocks, footer)
+}
+
+// GetSectionBlocks converts attachment fields into section blocks, respecting Slack limit of fields per section
+func GetSectionBlocks(fields []slack.AttachmentField) []slack.Block {
+	blocks := make([]slack.Block, 0)
+
+	for i := 0; i < len(fields); i += maxSectionFields {
+		end := i + maxSectionFields
+		if end > len(fields) {
+			end = len(fields)
+		}
+
+		objects := make([]*slack.TextBlockObject, 0)
+		for _, f := range fields[i:end] {
+			objects = append(objects, slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*%s*\n%s", f.Title, f.Value), false, false))
+		}
+
+		blocks = append(blocks, slack.NewSectionBlock(nil, objects, nil))
+	}
+
+	return blocks
+}
+
+// GetFallbackText returns a plain text summary of a message, displayed in notifications
+func GetFallbackText(data *TemplateData) string {
+	return fmt.Sprintf("%s %s: %s", data.Repository, data.Workflow, data.Label())
+}
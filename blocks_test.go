@@ -0,0 +1,68 @@
+package main_test
+
+import (
+	"testing"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Status         string
+		Failed         bool
+		Additions      []slack.AttachmentField
+		ExpectedHeader string
+		ExpectedBlocks int
+	}
+
+	suite := map[string]test{
+		"Running": {
+			Status:         "running",
+			Failed:         false,
+			Additions:      []slack.AttachmentField{},
+			ExpectedHeader: ":yellow_square: proj: RUNNING",
+			ExpectedBlocks: 3,
+		},
+		"Failure Trap": {
+			Status:         "finished",
+			Failed:         true,
+			Additions:      []slack.AttachmentField{},
+			ExpectedHeader: ":red_square: proj: FAILED",
+			ExpectedBlocks: 3,
+		},
+		"Additional Fields": {
+			Status: "released",
+			Failed: false,
+			Additions: []slack.AttachmentField{
+				{Title: "key-1", Value: "value-1"},
+				{Title: "key-2", Value: "value-2"},
+			},
+			ExpectedHeader: ":green_square: proj: RELEASED",
+			ExpectedBlocks: 4,
+		},
+		"Fields Overflow": {
+			Status:         "unknown",
+			Failed:         false,
+			Additions:      make([]slack.AttachmentField, 11),
+			ExpectedHeader: ":white_large_square: proj: UNKNOWN",
+			ExpectedBlocks: 5,
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		blocks := app.GetBlocks(app.NewTemplateData(app.DefaultTheme, test.Status, test.Failed, test.Additions))
+
+		assert.Equal(test.ExpectedBlocks, len(blocks))
+		assert.Equal(test.ExpectedHeader, blocks[0].(*slack.HeaderBlock).Text.Text)
+		assert.Equal(slack.MBTContext, blocks[len(blocks)-1].BlockType())
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// conversationsPageSize is a number of channels requested per page of 'conversations.list'
+const conversationsPageSize = 1000
+
+// cacheMu serializes updates of a channel cache file by channels notified concurrently
+var cacheMu sync.Mutex
+
+// IsChannelName returns true for '#channel-name' references, as opposed to channel IDs
+func IsChannelName(channel string) bool {
+	return strings.HasPrefix(channel, "#")
+}
+
+// ResolveChannels returns a channel ID of every channel. '#channel-name' references are resolved via
+// a cache file, when provided, and by listing conversations visible to the bot otherwise
+func (s *Slack) ResolveChannels(cli Client, channels []string, cacheFile string) (map[string]string, error) {
+	ids := make(map[string]string)
+	cache := LoadChannelCache(cacheFile)
+
+	missing := make(map[string]bool)
+	for _, c := range channels {
+		name := strings.ToLower(strings.TrimPrefix(c, "#"))
+
+		switch {
+		case !IsChannelName(c):
+			ids[c] = c
+		case cache[name] != "":
+			ids[c] = cache[name]
+		default:
+			missing[name] = true
+		}
+	}
+
+	if len(missing) == 0 {
+		return ids, nil
+	}
+
+	found, err := s.listChannels(cli, missing)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing channels")
+	}
+
+	for _, c := range channels {
+		if _, ok := ids[c]; ok {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimPrefix(c, "#"))
+		if found[name] == "" {
+			return nil, errChannelNotFound(c)
+		}
+
+		ids[c] = found[name]
+		cache[name] = found[name]
+	}
+
+	if err := saveChannelCache(cacheFile, cache); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// RefreshChannel drops a stale channel ID of a '#channel-name' reference from a cache file, and resolves it again
+func (s *Slack) RefreshChannel(cli Client, channel, cacheFile string) (string, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	name := strings.ToLower(strings.TrimPrefix(channel, "#"))
+
+	cache := LoadChannelCache(cacheFile)
+	delete(cache, name)
+
+	found, err := s.listChannels(cli, map[string]bool{name: true})
+	if err != nil {
+		return "", errors.Wrap(err, "error listing channels")
+	}
+
+	if found[name] == "" {
+		if err := saveChannelCache(cacheFile, cache); err != nil {
+			return "", err
+		}
+
+		return "", errChannelNotFound(channel)
+	}
+
+	cache[name] = found[name]
+
+	if err := saveChannelCache(cacheFile, cache); err != nil {
+		return "", err
+	}
+
+	return found[name], nil
+}
+
+// errChannelNotFound returns an error of a channel name which could not be resolved
+func errChannelNotFound(channel string) error {
+	return errors.New(fmt.Sprintf("channel '%s' is not found or is not visible to the bot, invite the bot to a private channel or provide a channel ID", channel))
+}
+
+// saveChannelCache writes resolved channel IDs by their names, unless a cache file is not used
+func saveChannelCache(filename string, cache map[string]string) error {
+	if filename == "" {
+		return nil
+	}
+
+	b, _ := json.Marshal(cache)
+	if err := os.WriteFile(filename, b, 0644); err != nil {
+		return errors.Wrap(err, "error writing channel cache file")
+	}
+
+	return nil
+}
+
+// LoadChannelCache returns previously resolved channel IDs by their names, ignoring a missing or a corrupted cache
+func LoadChannelCache(filename string) map[string]string {
+	cache := make(map[string]string)
+
+	if filename == "" {
+		return cache
+	}
+
+	file, err := os.ReadFile(filename)
+	if err != nil {
+		return cache
+	}
+
+	if err := json.Unmarshal(file, &cache); err != nil {
+		return make(map[string]string)
+	}
+
+	return cache
+}
+
+// listChannels pages through conversations until every name is found, and returns IDs of found channels.
+// Private channels are skipped when a token lacks 'groups:read' scope.
+func (s *Slack) listChannels(cli Client, names map[string]bool) (map[string]string, error) {
+	found := make(map[string]string)
+
+	params := &slack.GetConversationsParameters{
+		ExcludeArchived: true,
+		Limit:           conversationsPageSize,
+		Types:           []string{"public_channel", "private_channel"},
+	}
+
+	for {
+		var channels []slack.Channel
+		var cursor string
+
+		err := s.Retry.Do(s.Context, func() error {
+			var err error
+			channels, cursor, err = cli.GetConversationsContext(s.Context, params)
+			return err
+		})
+		if IsSlackError(err, "missing_scope") && len(params.Types) > 1 {
+			params.Types = []string{"public_channel"}
+			params.Cursor = ""
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range channels {
+			if names[strings.ToLower(c.Name)] {
+				found[strings.ToLower(c.Name)] = c.ID
+			}
+		}
+
+		if cursor == "" || len(found) == len(names) {
+			return found, nil
+		}
+
+		params.Cursor = cursor
+	}
+}
@@ -0,0 +1,212 @@
+package main_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	app "action-notify-slack"
+
+	"action-notify-slack/mocks"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newChannel(id, name string) slack.Channel {
+	c := slack.Channel{}
+	c.ID = id
+	c.Name = name
+	return c
+}
+
+func TestResolveChannels(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Channels       []string
+		Cache          string
+		MockError      error
+		ExpectedOutput map[string]string
+		ExpectedCache  string
+		ExpectedCalls  int
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Channel IDs": {
+			Channels:       []string{"C0001", "C0002"},
+			Cache:          "",
+			MockError:      nil,
+			ExpectedOutput: map[string]string{"C0001": "C0001", "C0002": "C0002"},
+			ExpectedCache:  "",
+			ExpectedCalls:  0,
+			ExpectedError:  "",
+		},
+		"Paginated Names": {
+			Channels:       []string{"#General", "#deploys", "C0003"},
+			Cache:          "",
+			MockError:      nil,
+			ExpectedOutput: map[string]string{"#General": "C0001", "#deploys": "C0002", "C0003": "C0003"},
+			ExpectedCache:  `{"deploys":"C0002","general":"C0001"}`,
+			ExpectedCalls:  2,
+			ExpectedError:  "",
+		},
+		"Cached Names": {
+			Channels:       []string{"#deploys"},
+			Cache:          `{"deploys":"C0009"}`,
+			MockError:      nil,
+			ExpectedOutput: map[string]string{"#deploys": "C0009"},
+			ExpectedCache:  `{"deploys":"C0009"}`,
+			ExpectedCalls:  0,
+			ExpectedError:  "",
+		},
+		"Not Visible": {
+			Channels:       []string{"#private"},
+			Cache:          "",
+			MockError:      nil,
+			ExpectedOutput: nil,
+			ExpectedCache:  "",
+			ExpectedCalls:  2,
+			ExpectedError:  "channel '#private' is not found or is not visible to the bot, invite the bot to a private channel or provide a channel ID",
+		},
+		"slack.GetConversationsContext Error": {
+			Channels:       []string{"#deploys"},
+			Cache:          "",
+			MockError:      errors.New("reason"),
+			ExpectedOutput: nil,
+			ExpectedCache:  "",
+			ExpectedCalls:  1,
+			ExpectedError:  "error listing channels: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, err := os.MkdirTemp(os.TempDir(), "test-")
+		assert.Equal(nil, err, "preparation: error creating temporary directory")
+		defer os.RemoveAll(dir)
+
+		cache := filepath.Join(dir, "channels.json")
+		if test.Cache != "" {
+			err = os.WriteFile(cache, []byte(test.Cache), 0644)
+			assert.Equal(nil, err, "preparation: error writing cache file")
+		}
+
+		s := &app.Slack{Context: context.Background(), Retry: app.RetryPolicy{Attempts: 1}}
+
+		m := new(mocks.Client)
+		m.On("GetConversationsContext", s.Context, mock.MatchedBy(func(p *slack.GetConversationsParameters) bool {
+			return p.Cursor == ""
+		})).Return([]slack.Channel{newChannel("C0001", "general"), newChannel("C0005", "random")}, "page-2", test.MockError)
+		m.On("GetConversationsContext", s.Context, mock.MatchedBy(func(p *slack.GetConversationsParameters) bool {
+			return p.Cursor == "page-2"
+		})).Return([]slack.Channel{newChannel("C0002", "deploys")}, "", test.MockError)
+
+		result, err := s.ResolveChannels(m, test.Channels, cache)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+		m.AssertNumberOfCalls(t, "GetConversationsContext", test.ExpectedCalls)
+
+		if test.ExpectedCache != "" {
+			content, err := os.ReadFile(cache)
+			assert.Equal(nil, err)
+			assert.Equal(test.ExpectedCache, string(content))
+		}
+	}
+}
+
+func TestResolveChannelsMissingScope(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &app.Slack{Context: context.Background(), Retry: app.RetryPolicy{Attempts: 1}}
+
+	m := new(mocks.Client)
+	m.On("GetConversationsContext", s.Context, mock.MatchedBy(func(p *slack.GetConversationsParameters) bool {
+		return len(p.Types) > 1
+	})).Return(nil, "", slack.SlackErrorResponse{Err: "missing_scope"})
+	m.On("GetConversationsContext", s.Context, mock.MatchedBy(func(p *slack.GetConversationsParameters) bool {
+		return len(p.Types) == 1 && p.Types[0] == "public_channel"
+	})).Return([]slack.Channel{newChannel("C0002", "deploys")}, "", nil)
+
+	result, err := s.ResolveChannels(m, []string{"#deploys"}, "")
+
+	assert.Equal(nil, err)
+	assert.Equal(map[string]string{"#deploys": "C0002"}, result)
+	m.AssertNumberOfCalls(t, "GetConversationsContext", 2)
+}
+
+func TestRefreshChannel(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Channel        string
+		Cache          string
+		ExpectedOutput string
+		ExpectedCache  string
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Recreated Channel": {
+			Channel:        "#deploys",
+			Cache:          `{"deploys":"C0009","general":"C0001"}`,
+			ExpectedOutput: "C0002",
+			ExpectedCache:  `{"deploys":"C0002","general":"C0001"}`,
+			ExpectedError:  "",
+		},
+		"Removed Channel": {
+			Channel:        "#archived",
+			Cache:          `{"archived":"C0009","general":"C0001"}`,
+			ExpectedOutput: "",
+			ExpectedCache:  `{"general":"C0001"}`,
+			ExpectedError:  "channel '#archived' is not found or is not visible to the bot, invite the bot to a private channel or provide a channel ID",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, err := os.MkdirTemp(os.TempDir(), "test-")
+		assert.Equal(nil, err, "preparation: error creating temporary directory")
+		defer os.RemoveAll(dir)
+
+		cache := filepath.Join(dir, "channels.json")
+		err = os.WriteFile(cache, []byte(test.Cache), 0644)
+		assert.Equal(nil, err, "preparation: error writing cache file")
+
+		s := &app.Slack{Context: context.Background(), Retry: app.RetryPolicy{Attempts: 1}}
+
+		m := new(mocks.Client)
+		m.On("GetConversationsContext", s.Context, mock.Anything).
+			Return([]slack.Channel{newChannel("C0001", "general"), newChannel("C0002", "deploys")}, "", nil)
+
+		result, err := s.RefreshChannel(m, test.Channel, cache)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+
+		content, err := os.ReadFile(cache)
+		assert.Equal(nil, err)
+		assert.Equal(test.ExpectedCache, string(content))
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"action-notify-slack/internal/actions"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// DryRun represents a Client printing message payloads instead of sending them to Slack
+type DryRun struct {
+	Output io.Writer
+
+	mu sync.Mutex
+}
+
+// jsonValues are payload parameters which contain an encoded JSON
+var jsonValues = []string{"attachments", "blocks", "metadata"}
+
+// PostMessageContext prints a 'chat.postMessage' payload
+func (d *DryRun) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+	return channelID, "", d.print("chat.postMessage", channelID, options...)
+}
+
+// UpdateMessageContext prints a 'chat.update' payload
+func (d *DryRun) UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	return channelID, timestamp, "", d.print("chat.update", channelID, append(options, slack.MsgOptionUpdate(timestamp))...)
+}
+
+// DeleteMessageContext prints a 'chat.delete' payload
+func (d *DryRun) DeleteMessageContext(ctx context.Context, channel, messageTimestamp string) (string, string, error) {
+	return channel, messageTimestamp, d.print("chat.delete", channel, slack.MsgOptionDelete(messageTimestamp))
+}
+
+// PostEphemeralContext prints a 'chat.postEphemeral' payload
+func (d *DryRun) PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error) {
+	return "", d.print("chat.postEphemeral", channelID, append(options, slack.MsgOptionPostEphemeral(userID))...)
+}
+
+// GetUserByEmailContext finds no user, as Slack API is not called
+func (d *DryRun) GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error) {
+	return nil, slack.SlackErrorResponse{Err: "users_not_found"}
+}
+
+// GetConversationsContext lists no channels, as Slack API is not called
+func (d *DryRun) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	return nil, "", nil
+}
+
+// GetConversationHistoryContext returns an empty history, as Slack API is not called
+func (d *DryRun) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	return &slack.GetConversationHistoryResponse{}, nil
+}
+
+// GetPermalinkContext returns an empty permalink, as no message is sent
+func (d *DryRun) GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error) {
+	return "", nil
+}
+
+// UploadFileV2Context prints a file upload request, without a content of a file
+func (d *DryRun) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	payload := map[string]interface{}{
+		"channel_id": params.Channel,
+		"filename":   params.Filename,
+		"length":     params.FileSize,
+		"title":      params.Title,
+	}
+
+	if params.ThreadTimestamp != "" {
+		payload["thread_ts"] = params.ThreadTimestamp
+	}
+
+	return &slack.FileSummary{Title: params.Title}, d.printPayload("files.uploadV2", payload)
+}
+
+// ScheduleMessageContext prints a 'chat.scheduleMessage' payload
+func (d *DryRun) ScheduleMessageContext(ctx context.Context, channelID, postAt string, options ...slack.MsgOption) (string, string, error) {
+	return channelID, "", d.print("chat.scheduleMessage", channelID, append(options, slack.MsgOptionSchedule(postAt))...)
+}
+
+// DeleteScheduledMessageContext prints a 'chat.deleteScheduledMessage' payload
+func (d *DryRun) DeleteScheduledMessageContext(ctx context.Context, params *slack.DeleteScheduledMessageParameters) (bool, error) {
+	err := d.printPayload("chat.deleteScheduledMessage", map[string]interface{}{
+		"channel":              params.Channel,
+		"scheduled_message_id": params.ScheduledMessageID,
+	})
+
+	return err == nil, err
+}
+
+// GetPayload returns a JSON payload of a Slack API method
+func GetPayload(method, channelID string, options ...slack.MsgOption) ([]byte, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error applying message options")
+	}
+
+	payload := make(map[string]interface{})
+	for k := range values {
+		payload[k] = values.Get(k)
+	}
+
+	delete(payload, "token")
+
+	for _, k := range jsonValues {
+		if v := values.Get(k); v != "" {
+			payload[k] = json.RawMessage(v)
+		}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"method":  method,
+		"payload": payload,
+	}, "", "  ")
+}
+
+func (d *DryRun) print(method, channelID string, options ...slack.MsgOption) error {
+	p, err := GetPayload(method, channelID, options...)
+	if err != nil {
+		return err
+	}
+
+	return d.write(p)
+}
+
+func (d *DryRun) printPayload(method string, payload map[string]interface{}) error {
+	p, err := json.MarshalIndent(map[string]interface{}{
+		"method":  method,
+		"payload": payload,
+	}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error encoding payload")
+	}
+
+	return d.write(p)
+}
+
+func (d *DryRun) write(p []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := fmt.Fprintln(d.Output, string(p)); err != nil {
+		return errors.Wrap(err, "error printing payload")
+	}
+
+	if err := actions.AddStepSummary(fmt.Sprintf("#### Slack Payload (dry run)\n\n```json\n%s\n```\n\n", p)); err != nil {
+		return errors.Wrap(err, "error writing step summary")
+	}
+
+	return nil
+}
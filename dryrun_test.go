@@ -0,0 +1,100 @@
+package main_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	assert := assert.New(t)
+
+	summary, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(summary.Name())
+
+	os.Setenv("GITHUB_STEP_SUMMARY", summary.Name())
+	defer os.Unsetenv("GITHUB_STEP_SUMMARY")
+
+	type test struct {
+		Receiver       *app.Slack
+		Fields         []slack.AttachmentField
+		ExpectedMethod string
+		ExpectedKeys   []string
+	}
+
+	suite := map[string]test{
+		"Post": {
+			Receiver: &app.Slack{
+				Channel: "self",
+				Context: context.Background(),
+			},
+			Fields:         []slack.AttachmentField{{Title: "key", Value: "value"}},
+			ExpectedMethod: "chat.postMessage",
+			ExpectedKeys:   []string{"attachments", "channel"},
+		},
+		"Update": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: "1589146397.007200",
+			},
+			Fields:         []slack.AttachmentField{},
+			ExpectedMethod: "chat.update",
+			ExpectedKeys:   []string{"attachments", "channel", "ts"},
+		},
+		"Block Kit Thread Reply": {
+			Receiver: &app.Slack{
+				Channel:         "self",
+				Context:         context.Background(),
+				ThreadTimestamp: "1589146397.007200",
+				BlockKit:        true,
+			},
+			Fields:         []slack.AttachmentField{},
+			ExpectedMethod: "chat.postMessage",
+			ExpectedKeys:   []string{"blocks", "channel", "text", "thread_ts"},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		var out bytes.Buffer
+		cli := &app.DryRun{Output: &out}
+
+		_, err := test.Receiver.SendTemplate(cli, test.Fields)
+		assert.Equal(nil, err)
+
+		var result struct {
+			Method  string                     `json:"method"`
+			Payload map[string]json.RawMessage `json:"payload"`
+		}
+
+		err = json.Unmarshal(out.Bytes(), &result)
+		assert.Equal(nil, err)
+
+		assert.Equal(test.ExpectedMethod, result.Method)
+
+		keys := make([]string, 0)
+		for _, k := range []string{"attachments", "blocks", "channel", "text", "thread_ts", "ts"} {
+			if _, ok := result.Payload[k]; ok {
+				keys = append(keys, k)
+			}
+		}
+		assert.Equal(test.ExpectedKeys, keys)
+		assert.NotContains(result.Payload, "token")
+	}
+
+	content, err := os.ReadFile(summary.Name())
+	assert.Equal(nil, err)
+	assert.Equal(3, bytes.Count(content, []byte("#### Slack Payload (dry run)")))
+}
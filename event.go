@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// Event represents a subset of GitHub event payload
+type Event struct {
+	Ref         string                 `json:"ref"`
+	After       string                 `json:"after"`
+	HeadCommit  *Commit                `json:"head_commit"`
+	PullRequest *PullRequest           `json:"pull_request"`
+	Release     *Release               `json:"release"`
+	Inputs      map[string]interface{} `json:"inputs"`
+}
+
+// Commit represents a head commit of a push event
+type Commit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	Author  struct {
+		Name     string `json:"name"`
+		Email    string `json:"email"`
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+// PullRequest represents a pull request of a pull_request event
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Release represents a release of a release event
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	HTMLURL string `json:"html_url"`
+}
+
+// maxCommitMessage is a maximum length of a commit message displayed in a message
+const maxCommitMessage = 150
+
+// LoadEvent returns an event payload of a workflow run, or nil when unavailable
+func LoadEvent(path string) (*Event, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error reading event payload '%s'", path))
+	}
+
+	event := new(Event)
+	if err := json.Unmarshal(file, event); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("invalid event payload '%s'", path))
+	}
+
+	return event, nil
+}
+
+// GetEventFields returns context fields of an event
+func GetEventFields(data *TemplateData) []slack.AttachmentField {
+	fields := make([]slack.AttachmentField, 0)
+
+	e := data.Event
+	if e == nil {
+		return fields
+	}
+
+	switch data.EventName {
+	case "push":
+		if strings.HasPrefix(e.Ref, "refs/tags/") {
+			fields = append(fields, slack.AttachmentField{Title: "Tag", Value: strings.TrimPrefix(e.Ref, "refs/tags/"), Short: true})
+		} else if e.Ref != "" {
+			fields = append(fields, slack.AttachmentField{Title: "Branch", Value: strings.TrimPrefix(e.Ref, "refs/heads/"), Short: true})
+		}
+
+		if e.HeadCommit != nil {
+			url := e.HeadCommit.URL
+			if url == "" {
+				url = fmt.Sprintf("%s/%s/commit/%s", data.ServerURL, data.Repository, e.HeadCommit.ID)
+			}
+
+			fields = append(fields,
+				slack.AttachmentField{Title: "Commit", Value: fmt.Sprintf("<%s|%s>", url, shortSHA(e.HeadCommit.ID)), Short: true},
+				slack.AttachmentField{Title: "Message", Value: escape(truncate(maxCommitMessage, strings.SplitN(e.HeadCommit.Message, "\n", 2)[0])), Short: false},
+			)
+		}
+	case "pull_request", "pull_request_target":
+		if e.PullRequest != nil {
+			fields = append(fields, slack.AttachmentField{
+				Title: "Pull Request",
+				Value: fmt.Sprintf("<%s|#%v> %s", e.PullRequest.HTMLURL, e.PullRequest.Number, escape(e.PullRequest.Title)),
+				Short: false,
+			})
+		}
+	case "release":
+		if e.Release != nil {
+			fields = append(fields, slack.AttachmentField{
+				Title: "Release",
+				Value: fmt.Sprintf("<%s|%s>", e.Release.HTMLURL, e.Release.TagName),
+				Short: true,
+			})
+		}
+	case "workflow_dispatch":
+		if len(e.Inputs) > 0 {
+			keys := make([]string, 0)
+			for k := range e.Inputs {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			lines := make([]string, 0)
+			for _, k := range keys {
+				// inputs are provided by a user triggering a workflow, hence never trusted as Slack markup
+				lines = append(lines, fmt.Sprintf("%s: `%s`", escape(k), escape(fmt.Sprint(e.Inputs[k]))))
+			}
+
+			fields = append(fields, slack.AttachmentField{Title: "Inputs", Value: strings.Join(lines, "\n"), Short: false})
+		}
+	}
+
+	return fields
+}
+
+// shortSHA returns an abbreviated commit SHA
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+
+	return sha
+}
+
+// escape escapes control characters of Slack message formatting
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,124 @@
+package main_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEventFields(t *testing.T) {
+	assert := assert.New(t)
+
+	filename, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(filename.Name())
+
+	type test struct {
+		EventName      string
+		Payload        string
+		ExpectedFields []slack.AttachmentField
+	}
+
+	suite := map[string]test{
+		"Push Branch": {
+			EventName: "push",
+			Payload:   `{"ref": "refs/heads/main", "head_commit": {"id": "0123456789abcdef", "message": "fix <parser> & lexer\n\nlong description", "url": "https://github.com/org/proj/commit/0123456789abcdef"}}`,
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "Branch", Value: "main", Short: true},
+				{Title: "Commit", Value: "<https://github.com/org/proj/commit/0123456789abcdef|0123456>", Short: true},
+				{Title: "Message", Value: "fix &lt;parser&gt; &amp; lexer", Short: false},
+			},
+		},
+		"Push without Commit URL": {
+			EventName: "push",
+			Payload:   `{"ref": "refs/heads/main", "head_commit": {"id": "0123456789abcdef", "message": "fix"}}`,
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "Branch", Value: "main", Short: true},
+				{Title: "Commit", Value: "<https://github.example.com/org/proj/commit/0123456789abcdef|0123456>", Short: true},
+				{Title: "Message", Value: "fix", Short: false},
+			},
+		},
+		"Push Tag": {
+			EventName: "push",
+			Payload:   `{"ref": "refs/tags/v1.2.3"}`,
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "Tag", Value: "v1.2.3", Short: true},
+			},
+		},
+		"Pull Request": {
+			EventName: "pull_request",
+			Payload:   `{"pull_request": {"number": 42, "title": "Add feature", "html_url": "https://github.com/org/proj/pull/42"}}`,
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "Pull Request", Value: "<https://github.com/org/proj/pull/42|#42> Add feature", Short: false},
+			},
+		},
+		"Release": {
+			EventName: "release",
+			Payload:   `{"release": {"tag_name": "v1.2.3", "html_url": "https://github.com/org/proj/releases/tag/v1.2.3"}}`,
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "Release", Value: "<https://github.com/org/proj/releases/tag/v1.2.3|v1.2.3>", Short: true},
+			},
+		},
+		"Workflow Dispatch": {
+			EventName: "workflow_dispatch",
+			Payload:   `{"inputs": {"environment": "production", "dry": false}}`,
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "Inputs", Value: "dry: `false`\nenvironment: `production`", Short: false},
+			},
+		},
+		"Workflow Dispatch Markup": {
+			EventName: "workflow_dispatch",
+			Payload:   `{"inputs": {"reason": "<!channel> deploy & <b>"}}`,
+			ExpectedFields: []slack.AttachmentField{
+				{Title: "Inputs", Value: "reason: `&lt;!channel&gt; deploy &amp; &lt;b&gt;`", Short: false},
+			},
+		},
+		"Unsupported Event": {
+			EventName:      "schedule",
+			Payload:        `{"schedule": "0 0 * * *"}`,
+			ExpectedFields: []slack.AttachmentField{},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		err = os.WriteFile(filename.Name(), []byte(test.Payload), 0644)
+		assert.Equal(nil, err, "preparation: error writing event payload to temporary file")
+
+		event, err := app.LoadEvent(filename.Name())
+		assert.Equal(nil, err)
+
+		fields := app.GetEventFields(&app.TemplateData{ServerURL: "https://github.example.com", Repository: "org/proj", EventName: test.EventName, Event: event})
+
+		assert.Equal(test.ExpectedFields, fields)
+	}
+}
+
+func TestLoadEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	event, err := app.LoadEvent("")
+	assert.Equal(nil, err)
+	assert.Nil(event)
+
+	_, err = app.LoadEvent("/non/existing/event.json")
+	assert.ErrorContains(err, "error reading event payload '/non/existing/event.json'")
+
+	filename, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(filename.Name())
+
+	err = os.WriteFile(filename.Name(), []byte(`{"ref": `), 0644)
+	assert.Equal(nil, err, "preparation: error writing event payload to temporary file")
+
+	_, err = app.LoadEvent(filename.Name())
+	assert.EqualError(err, fmt.Sprintf("invalid event payload '%s': unexpected end of JSON input", filename.Name()))
+}
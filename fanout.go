@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ParseChannels returns a list of unique channels separated by commas, whitespace or new lines
+func ParseChannels(s string) []string {
+	channels := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, c := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	}) {
+		if !seen[c] {
+			seen[c] = true
+			channels = append(channels, c)
+		}
+	}
+
+	return channels
+}
+
+// ParseTimestamps returns a timestamp per channel. Accepts either a plain timestamp of a single channel
+// or a JSON object of channel to timestamp
+func ParseTimestamps(s string, channels []string) (map[string]string, error) {
+	timestamps := make(map[string]string)
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return timestamps, nil
+	}
+
+	if strings.HasPrefix(s, "{") {
+		if err := json.Unmarshal([]byte(s), &timestamps); err != nil {
+			return timestamps, errors.Wrap(err, "invalid timestamps JSON")
+		}
+
+		return timestamps, nil
+	}
+
+	if len(channels) != 1 {
+		return timestamps, errors.New(fmt.Sprintf("timestamp '%s' is ambiguous for %v channels, provide a JSON object of channel to timestamp", s, len(channels)))
+	}
+
+	timestamps[channels[0]] = s
+
+	return timestamps, nil
+}
+
+// FanOut concurrently executes a function for each channel and returns a timestamp per channel.
+// Errors of all channels are collected, timestamps of successful channels are returned anyway.
+func FanOut(channels []string, fn func(channel string) (string, error)) (map[string]string, error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	timestamps := make(map[string]string)
+	failures := make([]string, 0)
+
+	for _, c := range channels {
+		wg.Add(1)
+
+		go func(channel string) {
+			defer wg.Done()
+
+			ts, err := fn(channel)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("channel '%s': %s", channel, err))
+				return
+			}
+
+			timestamps[channel] = ts
+		}(c)
+	}
+
+	wg.Wait()
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return timestamps, errors.New(strings.Join(failures, "; "))
+	}
+
+	return timestamps, nil
+}
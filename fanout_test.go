@@ -0,0 +1,138 @@
+package main_test
+
+import (
+	"testing"
+
+	app "action-notify-slack"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChannels(t *testing.T) {
+	assert := assert.New(t)
+
+	suite := map[string]struct {
+		Input          string
+		ExpectedOutput []string
+	}{
+		"Single":     {Input: "C0001", ExpectedOutput: []string{"C0001"}},
+		"Commas":     {Input: "C0001,C0002, C0003", ExpectedOutput: []string{"C0001", "C0002", "C0003"}},
+		"New Lines":  {Input: "C0001\nC0002\n", ExpectedOutput: []string{"C0001", "C0002"}},
+		"Duplicates": {Input: "C0001,C0001", ExpectedOutput: []string{"C0001"}},
+		"Empty":      {Input: " , ", ExpectedOutput: []string{}},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		assert.Equal(test.ExpectedOutput, app.ParseChannels(test.Input))
+	}
+}
+
+func TestParseTimestamps(t *testing.T) {
+	assert := assert.New(t)
+
+	suite := map[string]struct {
+		Input          string
+		Channels       []string
+		ExpectedOutput map[string]string
+		ExpectedError  string
+	}{
+		"Empty": {
+			Input:          "",
+			Channels:       []string{"C0001", "C0002"},
+			ExpectedOutput: map[string]string{},
+			ExpectedError:  "",
+		},
+		"Plain": {
+			Input:          "1589146397.007200\n",
+			Channels:       []string{"C0001"},
+			ExpectedOutput: map[string]string{"C0001": "1589146397.007200"},
+			ExpectedError:  "",
+		},
+		"JSON": {
+			Input:          `{"C0001":"1589146397.007200","C0002":"1589146398.007200"}`,
+			Channels:       []string{"C0001", "C0002"},
+			ExpectedOutput: map[string]string{"C0001": "1589146397.007200", "C0002": "1589146398.007200"},
+			ExpectedError:  "",
+		},
+		"Invalid JSON": {
+			Input:          `{"C0001":`,
+			Channels:       []string{"C0001"},
+			ExpectedOutput: map[string]string{},
+			ExpectedError:  "invalid timestamps JSON: unexpected end of JSON input",
+		},
+		"Ambiguous": {
+			Input:          "1589146397.007200",
+			Channels:       []string{"C0001", "C0002"},
+			ExpectedOutput: map[string]string{},
+			ExpectedError:  "timestamp '1589146397.007200' is ambiguous for 2 channels, provide a JSON object of channel to timestamp",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		result, err := app.ParseTimestamps(test.Input, test.Channels)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+			assert.Equal(test.ExpectedOutput, result)
+		}
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Channels       []string
+		Failing        map[string]bool
+		ExpectedOutput map[string]string
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"All Succeeded": {
+			Channels:       []string{"C0001", "C0002", "C0003"},
+			Failing:        map[string]bool{},
+			ExpectedOutput: map[string]string{"C0001": "ts-C0001", "C0002": "ts-C0002", "C0003": "ts-C0003"},
+			ExpectedError:  "",
+		},
+		"Partial Failure": {
+			Channels:       []string{"C0001", "C0002", "C0003"},
+			Failing:        map[string]bool{"C0001": true, "C0003": true},
+			ExpectedOutput: map[string]string{"C0002": "ts-C0002"},
+			ExpectedError:  "channel 'C0001': reason; channel 'C0003': reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		result, err := app.FanOut(test.Channels, func(channel string) (string, error) {
+			if test.Failing[channel] {
+				return "", errors.New("reason")
+			}
+
+			return "ts-" + channel, nil
+		})
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// maxHistory is a maximum number of the latest status changes displayed in a timeline
+const maxHistory = 20
+
+// GetHistoryField returns a timeline of status changes of a run, for example 'building 12:01 → deploying 12:07'
+func GetHistoryField(history []StatusChange) slack.AttachmentField {
+	entries := make([]string, 0)
+
+	if len(history) > maxHistory {
+		entries = append(entries, "…")
+		history = history[len(history)-maxHistory:]
+	}
+
+	for _, h := range history {
+		status := h.Status
+		if h.Failed {
+			status = fmt.Sprintf("%s (failed)", status)
+		}
+
+		entries = append(entries, fmt.Sprintf("%s <!date^%v^{time}|%s>", escape(status), h.Time.Unix(), h.Time.UTC().Format("15:04 UTC")))
+	}
+
+	return slack.AttachmentField{Title: "History", Value: strings.Join(entries, " → "), Short: false}
+}
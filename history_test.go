@@ -0,0 +1,75 @@
+package main_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHistoryField(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2020, 5, 10, 12, 1, 0, 0, time.UTC)
+
+	long := make([]app.StatusChange, 0)
+	for i := 0; i < 25; i++ {
+		long = append(long, app.StatusChange{Status: fmt.Sprintf("step-%v", i), Time: start})
+	}
+
+	type test struct {
+		History        []app.StatusChange
+		ExpectedOutput slack.AttachmentField
+	}
+
+	suite := map[string]test{
+		"Single Status": {
+			History: []app.StatusChange{{Status: "building", Time: start}},
+			ExpectedOutput: slack.AttachmentField{
+				Title: "History",
+				Value: "building <!date^1589112060^{time}|12:01 UTC>",
+				Short: false,
+			},
+		},
+		"Timeline": {
+			History: []app.StatusChange{
+				{Status: "building", Time: start},
+				{Status: "deploying", Time: start.Add(6 * time.Minute)},
+				{Status: "released", Time: start.Add(14 * time.Minute)},
+			},
+			ExpectedOutput: slack.AttachmentField{
+				Title: "History",
+				Value: "building <!date^1589112060^{time}|12:01 UTC> → deploying <!date^1589112420^{time}|12:07 UTC> → released <!date^1589112900^{time}|12:15 UTC>",
+				Short: false,
+			},
+		},
+		"Failure": {
+			History: []app.StatusChange{{Status: "<deploying>", Failed: true, Time: start}},
+			ExpectedOutput: slack.AttachmentField{
+				Title: "History",
+				Value: "&lt;deploying&gt; (failed) <!date^1589112060^{time}|12:01 UTC>",
+				Short: false,
+			},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		assert.Equal(test.ExpectedOutput, app.GetHistoryField(test.History))
+	}
+
+	t.Log("Test Case - Truncated Timeline")
+
+	entries := strings.Split(app.GetHistoryField(long).Value, " → ")
+	assert.Equal(21, len(entries))
+	assert.Equal("…", entries[0])
+	assert.True(strings.HasPrefix(entries[1], "step-5 "))
+}
@@ -0,0 +1,116 @@
+// Package actions implements GitHub Actions workflow commands and environment files,
+// without relying on a shell being available in a container.
+package actions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Stdout is where workflow commands are written to, watched by a runner
+var Stdout io.Writer = os.Stdout
+
+// SetOutput sets an output parameter of a step
+func SetOutput(name, value string) error {
+	return appendKeyValue("GITHUB_OUTPUT", name, value)
+}
+
+// SaveState saves a value shared with pre/post actions of a step
+func SaveState(name, value string) error {
+	return appendKeyValue("GITHUB_STATE", name, value)
+}
+
+// AddStepSummary appends a markdown to a job summary
+func AddStepSummary(markdown string) error {
+	return appendFile("GITHUB_STEP_SUMMARY", markdown)
+}
+
+// AddMask masks a value in a log of a job
+func AddMask(value string) {
+	if value == "" {
+		return
+	}
+
+	command("add-mask", value)
+}
+
+// Error creates an error annotation
+func Error(message string) {
+	command("error", message)
+}
+
+// Warning creates a warning annotation
+func Warning(message string) {
+	command("warning", message)
+}
+
+// Notice creates a notice annotation
+func Notice(message string) {
+	command("notice", message)
+}
+
+// command writes a workflow command
+func command(name, message string) {
+	fmt.Fprintf(Stdout, "::%s::%s\n", name, escape(message))
+}
+
+// escape escapes a message of a workflow command, so it is kept on a single line
+func escape(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// appendKeyValue appends a key-value pair to an environment file,
+// using a heredoc-like delimiter for values which span multiple lines
+func appendKeyValue(env, key, value string) error {
+	if !strings.ContainsAny(value, "\r\n") {
+		return appendFile(env, fmt.Sprintf("%s=%s\n", key, value))
+	}
+
+	delimiter, err := getDelimiter(value)
+	if err != nil {
+		return err
+	}
+
+	return appendFile(env, fmt.Sprintf("%s<<%s\n%s\n%s\n", key, delimiter, value, delimiter))
+}
+
+// getDelimiter returns a random delimiter which does not occur in a value
+func getDelimiter(value string) (string, error) {
+	for {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", errors.Wrap(err, "error generating delimiter")
+		}
+
+		delimiter := "ghadelimiter_" + hex.EncodeToString(b)
+		if !strings.Contains(value, delimiter) {
+			return delimiter, nil
+		}
+	}
+}
+
+// appendFile appends a content to a file referenced by an env.var, doing nothing when it is unset
+func appendFile(env, content string) error {
+	filename := os.Getenv(env)
+	if filename == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error opening file of env.var '%s'", env))
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(content); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error writing file of env.var '%s'", env))
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package actions_test
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"testing"
+
+	"action-notify-slack/internal/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Name           string
+		Value          string
+		ExpectedOutput *regexp.Regexp
+	}
+
+	suite := map[string]test{
+		"Single Line": {
+			Name:           "TIMESTAMP",
+			Value:          "1589146397.007200",
+			ExpectedOutput: regexp.MustCompile(`^TIMESTAMP=1589146397\.007200\n$`),
+		},
+		"Empty": {
+			Name:           "PERMALINK",
+			Value:          "",
+			ExpectedOutput: regexp.MustCompile(`^PERMALINK=\n$`),
+		},
+		"Multiple Lines": {
+			Name:           "LOG",
+			Value:          "line-1\nline-2",
+			ExpectedOutput: regexp.MustCompile(`^LOG<<(ghadelimiter_[0-9a-f]{32})\nline-1\nline-2\n(ghadelimiter_[0-9a-f]{32})\n$`),
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		file, err := os.CreateTemp(os.TempDir(), "test-")
+		assert.Equal(nil, err, "preparation: error creating temporary file")
+		defer os.Remove(file.Name())
+
+		os.Setenv("GITHUB_OUTPUT", file.Name())
+
+		err = actions.SetOutput(test.Name, test.Value)
+		assert.Equal(nil, err)
+
+		result, err := os.ReadFile(file.Name())
+		assert.Equal(nil, err)
+
+		match := test.ExpectedOutput.FindStringSubmatch(string(result))
+		assert.NotNil(match, string(result))
+
+		if len(match) == 3 {
+			assert.Equal(match[1], match[2], "delimiters should match")
+		}
+	}
+
+	os.Unsetenv("GITHUB_OUTPUT")
+}
+
+func TestEnvironmentFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	state, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(state.Name())
+
+	summary, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(summary.Name())
+
+	os.Setenv("GITHUB_STATE", state.Name())
+	os.Setenv("GITHUB_STEP_SUMMARY", summary.Name())
+	defer os.Unsetenv("GITHUB_STATE")
+	defer os.Unsetenv("GITHUB_STEP_SUMMARY")
+
+	assert.Equal(nil, actions.SaveState("key", "value"))
+	assert.Equal(nil, actions.AddStepSummary("# first\n"))
+	assert.Equal(nil, actions.AddStepSummary("# second\n"))
+
+	result, err := os.ReadFile(state.Name())
+	assert.Equal(nil, err)
+	assert.Equal("key=value\n", string(result))
+
+	result, err = os.ReadFile(summary.Name())
+	assert.Equal(nil, err)
+	assert.Equal("# first\n# second\n", string(result))
+
+	os.Unsetenv("GITHUB_STEP_SUMMARY")
+	assert.Equal(nil, actions.AddStepSummary("ignored"), "unset env.var should be ignored")
+}
+
+func TestCommands(t *testing.T) {
+	assert := assert.New(t)
+
+	var out bytes.Buffer
+	actions.Stdout = &out
+	defer func() { actions.Stdout = os.Stdout }()
+
+	actions.AddMask("secret")
+	actions.AddMask("")
+	actions.Error("error sending message: 100% failed\nretry")
+	actions.Warning("warning")
+	actions.Notice("notice")
+
+	expected := "::add-mask::secret\n" +
+		"::error::error sending message: 100%25 failed%0Aretry\n" +
+		"::warning::warning\n" +
+		"::notice::notice\n"
+
+	assert.Equal(expected, out.String())
+}
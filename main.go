@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
-	"os/exec"
+
+	"action-notify-slack/internal/actions"
 
 	"github.com/pkg/errors"
 	"github.com/slack-go/slack"
@@ -15,12 +19,33 @@ import (
 
 // Config contains parsed user configuration
 type Config struct {
-	Channel         string
-	AttachmentsFile string
-	TimestampFile   string
-	Timestamp       string
-	Fields          []slack.AttachmentField
-	Client          *slack.Client
+	Channels         []string
+	ChannelIDs       map[string]string
+	AttachmentsFile  string
+	TemplateFile     string
+	TimestampFile    string
+	State            *State
+	Timestamps       map[string]string
+	ThreadTimestamps map[string]string
+	Broadcast        bool
+	Fields           []slack.AttachmentField
+	BlockKit         bool
+	Theme            *Theme
+	Retry            RetryPolicy
+	Timeout          time.Duration
+	DryRun           bool
+	Delete           bool
+	PostAt           time.Time
+	ScheduledIDs     map[string]string
+	EphemeralUser    string
+	Users            Users
+	LookupByEmail    bool
+	FindRunMessage   bool
+	History          bool
+	Files            []string
+	LogFile          string
+	LogTail          int
+	Client           Client
 }
 
 // GetConfig returns validated config params
@@ -72,82 +97,812 @@ func GetConfig(args []string) (*Config, error) {
 		timestamp = os.Getenv("TIMESTAMP")
 	}
 
-	channel := os.Getenv("CHANNEL")
-	if channel == "" {
+	webhookURL := os.Getenv("WEBHOOK_URL")
+
+	dryRun, err := getBool("DRY_RUN")
+	if err != nil {
+		return conf, err
+	}
+
+	channels := ParseChannels(os.Getenv("CHANNEL"))
+	if len(channels) == 0 && webhookURL == "" && !dryRun {
 		return conf, errors.New("missing Slack channel")
+	} else if len(channels) == 0 {
+		// webhook posts to a channel it was created for
+		channels = []string{""}
+	}
+
+	var state *State
+	var timestamps map[string]string
+	if timestampFile != "" {
+		state, err = ParseState(timestamp, channels)
+		if err == nil {
+			timestamps = state.Timestamps()
+		}
+	} else {
+		timestamps, err = ParseTimestamps(timestamp, channels)
+	}
+
+	if err != nil {
+		return conf, errors.Wrap(err, "error parsing timestamp")
 	}
 
 	attachmentsFile := os.Getenv("ATTACHMENTS_FILE")
+	templateFile := os.Getenv("TEMPLATE_FILE")
+	if attachmentsFile != "" && templateFile != "" {
+		return conf, errors.New("'ATTACHMENTS_FILE' and 'TEMPLATE_FILE' are mutually exclusive")
+	}
+
+	blockKit, err := getBool("BLOCK_KIT")
+	if err != nil {
+		return conf, err
+	}
+
+	threadTimestamps, err := ParseTimestamps(os.Getenv("THREAD_TS"), channels)
+	if err != nil {
+		return conf, errors.Wrap(err, "error parsing thread timestamp")
+	}
+
+	replyInThread, err := getBool("REPLY_IN_THREAD")
+	if err != nil {
+		return conf, err
+	}
+
+	if len(threadTimestamps) == 0 && replyInThread {
+		threadTimestamps = timestamps
+	}
+
+	broadcast, err := getBool("BROADCAST_ON_FAILURE")
+	if err != nil {
+		return conf, err
+	}
+
+	theme := DefaultTheme
+	if os.Getenv("THEME_FILE") != "" {
+		theme, err = LoadTheme(os.Getenv("THEME_FILE"))
+		if err != nil {
+			return conf, err
+		}
+	}
+
+	retry := DefaultRetryPolicy
+
+	if os.Getenv("RETRY_ATTEMPTS") != "" {
+		retry.Attempts, err = strconv.Atoi(os.Getenv("RETRY_ATTEMPTS"))
+		if err != nil || retry.Attempts < 1 {
+			return conf, errors.New("env.var 'RETRY_ATTEMPTS' should be a positive number")
+		}
+	}
+
+	retry.BaseDelay, err = getDuration("RETRY_DELAY", retry.BaseDelay)
+	if err != nil {
+		return conf, err
+	}
+
+	if retry.BaseDelay < 0 {
+		return conf, errors.New("env.var 'RETRY_DELAY' should not be a negative duration")
+	}
+
+	retry.MaxDelay, err = getDuration("RETRY_MAX_DELAY", retry.MaxDelay)
+	if err != nil {
+		return conf, err
+	}
+
+	if retry.MaxDelay < 0 {
+		return conf, errors.New("env.var 'RETRY_MAX_DELAY' should not be a negative duration")
+	}
+
+	timeout, err := getDuration("TIMEOUT", 30*time.Second)
+	if err != nil {
+		return conf, err
+	}
+
+	if timeout <= 0 {
+		return conf, errors.New("env.var 'TIMEOUT' should be a positive duration")
+	}
+
+	deleteMessage, err := getBool("DELETE")
+	if err != nil {
+		return conf, err
+	}
+
+	scheduledIDs, err := ParseTimestamps(os.Getenv("SCHEDULED_MESSAGE_ID"), channels)
+	if err != nil {
+		return conf, errors.Wrap(err, "error parsing scheduled message id")
+	}
+
+	if deleteMessage && timestampFile == "" && os.Getenv("TIMESTAMP") == "" && len(scheduledIDs) == 0 {
+		return conf, errors.New("'DELETE' requires either 'TIMESTAMP', 'TIMESTAMP_FILE' or 'SCHEDULED_MESSAGE_ID'")
+	}
+
+	var postAt time.Time
+	if os.Getenv("POST_AT") != "" {
+		postAt, err = ParsePostAt(os.Getenv("POST_AT"), time.Now())
+		if err != nil {
+			return conf, errors.Wrap(err, "error parsing env.var 'POST_AT'")
+		}
+	}
+
+	files := ParseGlobs(os.Getenv("FILES"))
+	logFile := os.Getenv("LOG_FILE")
+
+	var logTail int
+	if logFile != "" {
+		logTail = DefaultLogTail
+	}
+
+	if os.Getenv("LOG_TAIL") != "" {
+		logTail, err = strconv.Atoi(os.Getenv("LOG_TAIL"))
+		if err != nil || logTail < 1 {
+			return conf, errors.New("env.var 'LOG_TAIL' should be a positive number")
+		}
+
+		if logFile == "" {
+			return conf, errors.New("env.var 'LOG_TAIL' requires 'LOG_FILE'")
+		}
+	}
+
+	if !postAt.IsZero() {
+		switch {
+		case deleteMessage:
+			return conf, errors.New("'POST_AT' and 'DELETE' are mutually exclusive")
+		case len(timestamps) > 0 && len(threadTimestamps) == 0:
+			return conf, errors.New("'POST_AT' cannot be used to update a message")
+		case len(files) > 0 || logFile != "":
+			return conf, errors.New("'POST_AT' cannot be used to upload files")
+		}
+	}
+
+	ephemeralUser := strings.TrimSpace(os.Getenv("EPHEMERAL_USER"))
+	if ephemeralUser != "" {
+		switch {
+		case deleteMessage:
+			return conf, errors.New("'EPHEMERAL_USER' and 'DELETE' are mutually exclusive")
+		case !postAt.IsZero():
+			return conf, errors.New("'EPHEMERAL_USER' and 'POST_AT' are mutually exclusive")
+		case len(timestamps) > 0 && len(threadTimestamps) == 0:
+			return conf, errors.New("'EPHEMERAL_USER' cannot be used to update a message, ephemeral messages can not be updated")
+		case len(files) > 0 || logFile != "":
+			return conf, errors.New("'EPHEMERAL_USER' cannot be used to upload files")
+		}
+	}
+
+	users := make(Users)
+	if os.Getenv("USERS_FILE") != "" {
+		users, err = LoadUsers(os.Getenv("USERS_FILE"))
+		if err != nil {
+			return conf, err
+		}
+	}
+
+	lookupByEmail, err := getBool("LOOKUP_BY_EMAIL")
+	if err != nil {
+		return conf, err
+	}
+
+	findRunMessage, err := getBool("FIND_RUN_MESSAGE")
+	if err != nil {
+		return conf, err
+	}
+
+	if findRunMessage {
+		switch {
+		case !postAt.IsZero():
+			return conf, errors.New("'FIND_RUN_MESSAGE' and 'POST_AT' are mutually exclusive")
+		case ephemeralUser != "":
+			return conf, errors.New("'FIND_RUN_MESSAGE' and 'EPHEMERAL_USER' are mutually exclusive")
+		}
+	}
+
+	history, err := getBool("HISTORY")
+	if err != nil {
+		return conf, err
+	}
+
+	if history && timestampFile == "" {
+		return conf, errors.New("'HISTORY' requires 'TIMESTAMP_FILE'")
+	}
+
+	declared := ParseStages(os.Getenv("STAGES"))
+	stage := strings.TrimSpace(os.Getenv("STAGE"))
+
+	stageStatus := strings.ToLower(strings.TrimSpace(os.Getenv("STAGE_STATUS")))
+	if stageStatus == "" {
+		stageStatus = StageRunning
+	}
+
+	if len(declared) > 0 || stage != "" {
+		if timestampFile == "" {
+			return conf, errors.New("'STAGES' and 'STAGE' require 'TIMESTAMP_FILE'")
+		}
+
+		if len(declared) > 0 {
+			state.DeclareStages(declared)
+		}
+
+		if stage != "" {
+			if err := state.MarkStage(stage, stageStatus, time.Now().UTC()); err != nil {
+				return conf, err
+			}
+		}
+	}
+
+	var cli Client
 
 	t := os.Getenv("TOKEN")
-	if t == "" {
+	if dryRun {
+		cli = &DryRun{Output: os.Stdout}
+	} else if webhookURL != "" {
+		if t != "" {
+			return conf, errors.New("'TOKEN' and 'WEBHOOK_URL' are mutually exclusive")
+		}
+
+		switch {
+		case deleteMessage:
+			return conf, ErrWebhookUnsupported("deleting messages")
+		case !postAt.IsZero():
+			return conf, ErrWebhookUnsupported("scheduling messages")
+		case ephemeralUser != "":
+			return conf, ErrWebhookUnsupported("sending ephemeral messages")
+		case lookupByEmail:
+			return conf, ErrWebhookUnsupported("looking up users by email")
+		case findRunMessage:
+			return conf, ErrWebhookUnsupported("finding messages of a run")
+		case len(channels) > 1:
+			return conf, ErrWebhookUnsupported("notifying multiple channels")
+		case timestampFile != "" || len(timestamps) > 0:
+			return conf, ErrWebhookUnsupported("updating messages via 'TIMESTAMP'/'TIMESTAMP_FILE'")
+		case len(threadTimestamps) > 0:
+			return conf, ErrWebhookUnsupported("replying in threads")
+		case len(files) > 0 || logFile != "":
+			return conf, ErrWebhookUnsupported("uploading files")
+		}
+
+		cli = &Webhook{URL: webhookURL}
+	} else if t == "" {
 		return conf, errors.New("missing Slack token")
+	} else {
+		cli = NewAPI(t, slack.APIURL)
 	}
 
-	conf.Channel = channel
+	conf.Channels = channels
 	conf.AttachmentsFile = attachmentsFile
+	conf.TemplateFile = templateFile
 	conf.TimestampFile = timestampFile
-	conf.Timestamp = timestamp
+	conf.State = state
+	conf.Timestamps = timestamps
+	conf.ThreadTimestamps = threadTimestamps
+	conf.Broadcast = broadcast
 	conf.Fields = fields
-	conf.Client = slack.New(t)
+	conf.BlockKit = blockKit
+	conf.Theme = theme
+	conf.Retry = retry
+	conf.Timeout = timeout
+	conf.DryRun = dryRun
+	conf.Delete = deleteMessage
+	conf.PostAt = postAt
+	conf.ScheduledIDs = scheduledIDs
+	conf.EphemeralUser = ephemeralUser
+	conf.Users = users
+	conf.LookupByEmail = lookupByEmail
+	conf.FindRunMessage = findRunMessage
+	conf.History = history
+	conf.Files = files
+	conf.LogFile = logFile
+	conf.LogTail = logTail
+	conf.Client = cli
 
 	return conf, nil
 }
 
-func main() {
-	vars := []string{
-		"GITHUB_ACTOR",
-		"GITHUB_REPOSITORY",
-		"STATUS",
-		"GITHUB_WORKFLOW",
+// getBool returns a parsed boolean env.var, defaulting to false when unset
+func getBool(name string) (bool, error) {
+	if os.Getenv(name) == "" {
+		return false, nil
+	}
+
+	v, err := strconv.ParseBool(os.Getenv(name))
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("error parsing env.var '%s'", name))
+	}
+
+	return v, nil
+}
+
+// getDuration returns a parsed duration env.var, defaulting to a fallback when unset
+func getDuration(name string, fallback time.Duration) (time.Duration, error) {
+	if os.Getenv(name) == "" {
+		return fallback, nil
+	}
+
+	v, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("error parsing env.var '%s'", name))
 	}
 
+	return v, nil
+}
+
+// requireEnv returns an error of the first missing env.var
+func requireEnv(vars ...string) error {
 	for _, v := range vars {
 		if os.Getenv(v) == "" {
-			fmt.Printf("missing required env.var: '%s'", v)
-			os.Exit(1)
+			return errors.New(fmt.Sprintf("missing required env.var: '%s'", v))
 		}
 	}
 
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		os.Exit(Run(os.Args[2:]))
+	}
+
+	if err := requireEnv("GITHUB_ACTOR", "GITHUB_REPOSITORY", "STATUS", "GITHUB_WORKFLOW"); err != nil {
+		actions.Error(err.Error())
+		os.Exit(1)
+	}
+
+	actions.AddMask(os.Getenv("TOKEN"))
+	actions.AddMask(os.Getenv("WEBHOOK_URL"))
+
 	conf, err := GetConfig(os.Args[1:])
 	if err != nil {
-		fmt.Println(err)
+		actions.Error(err.Error())
 		os.Exit(1)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), conf.Timeout)
 	defer cancel()
 
-	s := Slack{
-		Channel:   conf.Channel,
-		Context:   ctx,
-		Timestamp: conf.Timestamp,
+	if err := conf.resolveChannels(ctx); err != nil {
+		actions.Error(err.Error())
+		os.Exit(1)
 	}
 
-	var ts string
+	if conf.Delete && len(conf.ScheduledIDs) > 0 {
+		if err := unscheduleMessages(ctx, conf); err != nil {
+			actions.Error(err.Error())
+			os.Exit(1)
+		}
 
-	if conf.AttachmentsFile == "" {
-		ts, err = s.SendTemplate(conf.Client, conf.Fields)
-	} else {
-		ts, err = s.SendAttachmentFromFile(conf.Client, conf.AttachmentsFile, conf.Fields)
+		return
 	}
 
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if conf.Delete {
+		if err := DeleteMessages(ctx, conf); err != nil {
+			actions.Error(err.Error())
+			os.Exit(1)
+		}
+
+		return
 	}
 
-	if conf.TimestampFile != "" {
-		err = os.WriteFile(conf.TimestampFile, []byte(ts), 0644)
+	if conf.FindRunMessage {
+		conf.findRunMessages(ctx)
+	}
+
+	actorID := conf.getActorSlackID(ctx)
+
+	var mu sync.Mutex
+	parents := make(map[string]string)
+	permalinks := make(map[string]string)
+	refreshed := make(map[string]string)
+
+	timestamps, sendErr := FanOut(conf.Channels, func(channel string) (string, error) {
+		s := Slack{
+			Channel:         conf.ChannelIDs[channel],
+			Context:         ctx,
+			Timestamp:       conf.Timestamps[channel],
+			ThreadTimestamp: conf.ThreadTimestamps[channel],
+			Broadcast:       conf.Broadcast,
+			BlockKit:        conf.BlockKit,
+			Theme:           conf.Theme,
+			Retry:           conf.Retry,
+			PostAt:          conf.PostAt,
+			EphemeralUser:   conf.EphemeralUser,
+			ActorSlackID:    actorID,
+			StartedAt:       conf.startedAt(channel),
+			History:         conf.history(),
+			Stages:          conf.stages(),
+		}
+
+		ts, err := conf.sendRefreshing(&s, channel)
+		if s.Channel != conf.ChannelIDs[channel] {
+			mu.Lock()
+			refreshed[channel] = s.Channel
+			mu.Unlock()
+		}
+
 		if err != nil {
-			fmt.Println(err)
+			return "", err
+		}
+
+		// scheduled message is not posted yet, there is nothing to attach to
+		if !s.PostAt.IsZero() {
+			return ts, nil
+		}
+
+		// a message is already delivered, failed uploads should not fail a step
+		if err := conf.upload(&s, ts); err != nil {
+			actions.Warning(fmt.Sprintf("channel '%s': %s", channel, err))
+		}
+
+		if s.ThreadTimestamp == "" {
+			return ts, nil
+		}
+
+		// ephemeral messages have no permalinks
+		var link string
+		if s.EphemeralUser == "" {
+			link, err = s.GetPermalink(conf.Client, ts)
+			if err != nil {
+				// a reply is already delivered, a missing permalink should not fail a step
+				actions.Warning(fmt.Sprintf("channel '%s': %s", channel, err))
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// keep a parent message timestamp to attach further replies to the same thread
+		parents[channel] = s.ThreadTimestamp
+		permalinks[channel] = link
+
+		return ts, nil
+	})
+
+	// a timestamp file keeps channels resolved again
+	for channel, id := range refreshed {
+		conf.ChannelIDs[channel] = id
+	}
+
+	if len(timestamps) > 0 && !conf.PostAt.IsZero() {
+		if err := writeScheduledOutputs(conf, timestamps); err != nil {
+			actions.Error(err.Error())
 			os.Exit(1)
 		}
+	} else if len(timestamps) > 0 {
+		if err := WriteOutputs(conf, timestamps, parents, permalinks); err != nil {
+			actions.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if sendErr != nil {
+		actions.Error(sendErr.Error())
+		os.Exit(1)
+	}
+}
+
+// send sends a message to a single channel according to the configuration
+func (c *Config) send(s *Slack) (string, error) {
+	if c.AttachmentsFile != "" {
+		return s.SendAttachmentFromFile(c.Client, c.AttachmentsFile, c.Fields)
+	} else if c.TemplateFile != "" {
+		return s.SendTemplateFile(c.Client, c.TemplateFile, c.Fields)
+	}
+
+	return s.SendTemplate(c.Client, c.Fields)
+}
+
+// sendRefreshing sends a message, resolving a channel name again when a cached ID of a channel is stale,
+// for example of a renamed or recreated channel. A channel of a message is updated accordingly.
+func (c *Config) sendRefreshing(s *Slack, channel string) (string, error) {
+	ts, err := c.send(s)
+	if !IsSlackError(err, "channel_not_found") || !IsChannelName(channel) {
+		return ts, err
+	}
+
+	id, rerr := s.RefreshChannel(c.Client, channel, c.channelCache())
+	if rerr != nil {
+		return "", rerr
+	}
+
+	s.Channel = id
+
+	return c.send(s)
+}
+
+// channelCache returns a path of a channel cache file named after a timestamp file, or empty string when not used
+func (c *Config) channelCache() string {
+	if c.TimestampFile == "" {
+		return ""
+	}
+
+	return c.TimestampFile + ".channels.json"
+}
+
+// resolveChannels resolves an ID of every channel, caching names next to a timestamp file.
+// Dry run and webhooks do not call Slack API, so channels are passed as is.
+func (c *Config) resolveChannels(ctx context.Context) error {
+	_, webhook := c.Client.(*Webhook)
+	if c.DryRun || webhook {
+		c.ChannelIDs = make(map[string]string)
+		for _, channel := range c.Channels {
+			c.ChannelIDs[channel] = channel
+		}
+
+		return nil
+	}
+
+	s := Slack{Context: ctx, Retry: c.Retry}
+
+	ids, err := s.ResolveChannels(c.Client, c.Channels, c.channelCache())
+	if err != nil {
+		return err
+	}
+
+	c.ChannelIDs = ids
+
+	return nil
+}
+
+// findRunMessages looks up a message of the current run in every channel without a timestamp.
+// A failed lookup is reported as a warning, and a new message is posted instead.
+func (c *Config) findRunMessages(ctx context.Context) {
+	found, err := FanOut(c.Channels, func(channel string) (string, error) {
+		if c.Timestamps[channel] != "" {
+			return "", nil
+		}
+
+		s := Slack{
+			Channel: c.ChannelIDs[channel],
+			Context: ctx,
+			Retry:   c.Retry,
+		}
+
+		return s.FindRunMessage(c.Client)
+	})
+	if err != nil {
+		actions.Warning(err.Error())
+	}
+
+	// with 'REPLY_IN_THREAD' thread timestamps share the same map, so a found message becomes a parent of a thread
+	for channel, ts := range found {
+		if ts != "" {
+			c.Timestamps[channel] = ts
+		}
+	}
+}
+
+// startedAt returns a time the first message of a run was posted to a channel, according to a timestamp file
+// or a timestamp of a message being updated or replied to. Zero time is returned for a new message.
+func (c *Config) startedAt(channel string) time.Time {
+	if c.State != nil && !c.State.PostedAt.IsZero() {
+		return c.State.PostedAt
+	}
+
+	ts := c.ThreadTimestamps[channel]
+	if ts == "" {
+		ts = c.Timestamps[channel]
+	}
+
+	if ts == "" {
+		return time.Time{}
+	}
+
+	t, err := ParseMessageTime(ts)
+	if err != nil {
+		actions.Warning(fmt.Sprintf("channel '%s': %s", channel, err))
+		return time.Time{}
+	}
+
+	return t
+}
+
+// history returns a copy of status changes of a timestamp file, or nil when a history is not displayed
+func (c *Config) history() []StatusChange {
+	if !c.History {
+		return nil
+	}
+
+	return append(make([]StatusChange, 0, len(c.State.History)+1), c.State.History...)
+}
+
+// stages returns a copy of stages of a timestamp file, or nil when stages are not declared
+func (c *Config) stages() []Stage {
+	if c.State == nil || len(c.State.Stages) == 0 {
+		return nil
+	}
+
+	return append(make([]Stage, 0, len(c.State.Stages)), c.State.Stages...)
+}
+
+// getActorSlackID returns a Slack user ID of a GitHub actor, a failed lookup is reported as a warning
+func (c *Config) getActorSlackID(ctx context.Context) string {
+	if len(c.Users) == 0 && !c.LookupByEmail {
+		return ""
+	}
+
+	var commit *Commit
+	if c.LookupByEmail {
+		event, err := LoadEvent(os.Getenv("GITHUB_EVENT_PATH"))
+		if err != nil {
+			actions.Warning(err.Error())
+		} else if event != nil {
+			commit = event.HeadCommit
+		}
+	}
+
+	s := Slack{Context: ctx, Retry: c.Retry}
+
+	id, err := s.GetSlackUserID(c.Client, c.Users, os.Getenv("GITHUB_ACTOR"), commit, c.LookupByEmail)
+	if err != nil {
+		actions.Warning(err.Error())
+	}
+
+	return id
+}
+
+// DeleteMessages deletes a message of every channel and removes their timestamps from a timestamp file
+func DeleteMessages(ctx context.Context, conf *Config) error {
+	deleted, deleteErr := FanOut(conf.Channels, func(channel string) (string, error) {
+		s := Slack{
+			Channel:   conf.ChannelIDs[channel],
+			Context:   ctx,
+			Timestamp: conf.Timestamps[channel],
+			Retry:     conf.Retry,
+		}
+
+		// nothing was posted to this channel
+		if s.Timestamp == "" {
+			return "", nil
+		}
+
+		return s.Timestamp, s.Delete(conf.Client)
+	})
+
+	if conf.TimestampFile == "" || conf.DryRun {
+		return deleteErr
+	}
+
+	// keep messages that failed to be deleted, so a next attempt still reaches them
+	for channel := range deleted {
+		delete(conf.State.Messages, channel)
+	}
+
+	if len(conf.State.Messages) > 0 {
+		if err := conf.State.Save(conf.TimestampFile); err != nil {
+			return err
+		}
+
+		return deleteErr
+	}
+
+	if err := os.Remove(conf.TimestampFile); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "error removing timestamp file")
+	}
+
+	return deleteErr
+}
+
+// unscheduleMessages deletes a scheduled message of every channel
+func unscheduleMessages(ctx context.Context, conf *Config) error {
+	_, err := FanOut(conf.Channels, func(channel string) (string, error) {
+		s := Slack{
+			Channel: conf.ChannelIDs[channel],
+			Context: ctx,
+			Retry:   conf.Retry,
+		}
+
+		id := conf.ScheduledIDs[channel]
+		if id == "" {
+			return "", nil
+		}
+
+		return id, s.Unschedule(conf.Client, id)
+	})
+
+	return err
+}
+
+// upload uploads configured files and a log tail into a thread of a sent message
+func (c *Config) upload(s *Slack, ts string) error {
+	if len(c.Files) == 0 && c.LogFile == "" {
+		return nil
+	}
+
+	thread := s.ThreadTimestamp
+	if thread == "" {
+		thread = ts
+	}
+
+	files, err := GlobFiles(c.Files)
+	if err != nil {
+		return err
+	}
+
+	if err := s.UploadFiles(c.Client, files, thread); err != nil {
+		return err
+	}
+
+	if c.LogFile == "" {
+		return nil
+	}
+
+	return s.UploadLogTail(c.Client, c.LogFile, c.LogTail, thread)
+}
+
+// WriteOutputs stores timestamps of every channel in a timestamp file and step outputs
+func WriteOutputs(conf *Config, timestamps, parents, permalinks map[string]string) error {
+	// dry run does not produce timestamps, and ephemeral messages can not be updated, keep timestamp file as is
+	if conf.TimestampFile != "" && !conf.DryRun && (conf.EphemeralUser == "" || len(parents) > 0) {
+		if err := saveState(conf, timestamps, parents, permalinks); err != nil {
+			return err
+		}
+	}
+
+	first := conf.Channels[0]
+
+	outputs := map[string]string{
+		"TIMESTAMP":  timestamps[first],
+		"TIMESTAMPS": toJSON(timestamps),
+	}
+
+	if len(parents) > 0 {
+		outputs["THREAD_TS"] = parents[first]
+		outputs["THREAD_TIMESTAMPS"] = toJSON(parents)
+		outputs["PERMALINK"] = permalinks[first]
+		outputs["PERMALINKS"] = toJSON(permalinks)
+	}
+
+	for k, v := range outputs {
+		if err := actions.SetOutput(k, v); err != nil {
+			return errors.Wrap(err, "error setting step output")
+		}
 	}
-    cmd := exec.Command("/bin/sh","-c",fmt.Sprintf("echo \"TIMESTAMP=%s\" >> $GITHUB_OUTPUT",ts))
-    cmd.Stdout = os.Stdout
-    if err := cmd.Run(); err != nil {
-      fmt.Printf("error executing shell command: %v", err.Error())
-      os.Exit(1)
-      }
+
+	return nil
+}
+
+// saveState records sent messages and a status in a timestamp file. Messages of channels
+// that failed this time are kept, so a next update still reaches them
+func saveState(conf *Config, timestamps, parents, permalinks map[string]string) error {
+	failure, err := GetFailure()
+	if err != nil {
+		return err
+	}
+
+	status, _, err := GetStatus()
+	if err != nil {
+		return err
+	}
+
+	for channel, ts := range timestamps {
+		conf.State.Messages[channel] = Message{
+			Channel:         conf.ChannelIDs[channel],
+			Timestamp:       ts,
+			ThreadTimestamp: parents[channel],
+			Permalink:       permalinks[channel],
+		}
+	}
+
+	conf.State.Record(status, failure, conf.Fields, time.Now().UTC())
+
+	return conf.State.Save(conf.TimestampFile)
+}
+
+// writeScheduledOutputs sets step outputs of scheduled messages
+func writeScheduledOutputs(conf *Config, ids map[string]string) error {
+	outputs := map[string]string{
+		"SCHEDULED_MESSAGE_ID":  ids[conf.Channels[0]],
+		"SCHEDULED_MESSAGE_IDS": toJSON(ids),
+		"POST_AT":               strconv.FormatInt(conf.PostAt.Unix(), 10),
+	}
+
+	for k, v := range outputs {
+		if err := actions.SetOutput(k, v); err != nil {
+			return errors.Wrap(err, "error setting step output")
+		}
+	}
+
+	return nil
+}
+
+// toJSON returns a compact JSON representation of a map
+func toJSON(m map[string]string) string {
+	b, _ := json.Marshal(m)
+	return string(b)
 }
@@ -1,14 +1,20 @@
 package main_test
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	app "action-notify-slack"
 
+	"action-notify-slack/mocks"
+
 	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetConfig(t *testing.T) {
@@ -17,10 +23,44 @@ func TestGetConfig(t *testing.T) {
 	type test struct {
 		Channel         string
 		AttachmentsFile string
+		TemplateFile    string
 		Token           string
+		WebhookURL      string
+		DryRun          string
 		TimestampFile   bool
 		Timestamp       string
+		BlockKit        string
+		ThreadTimestamp string
+		ReplyInThread   string
+		Broadcast       string
+		RetryAttempts   string
+		RetryDelay      string
+		RetryMaxDelay   string
+		Timeout         string
+		Delete          string
+		PostAt          string
+		ScheduledID     string
+		EphemeralUser   string
+		Users           string
+		LookupByEmail   string
+		FindRunMessage  string
+		History         string
+		Stages          string
+		Stage           string
+		StageStatus     string
+		Files           string
+		LogFile         string
+		LogTail         string
 		Arguments       []string
+		ExpectedRetry   app.RetryPolicy
+		ExpectedTimeout time.Duration
+		ExpectedChannel []string
+		ExpectedTs      map[string]string
+		ExpectedThread  map[string]string
+		ExpectedPostAt  time.Time
+		ExpectedUsers   app.Users
+		ExpectedFiles   []string
+		ExpectedLogTail int
 		ExpectedFields  []slack.AttachmentField
 		ExpectedError   string
 	}
@@ -36,88 +76,703 @@ func TestGetConfig(t *testing.T) {
 			ExpectedFields:  []slack.AttachmentField{},
 			ExpectedError:   "",
 		},
-		"Timestamp File": {
+		"Timestamp File": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   true,
+			Timestamp:       "1589146397.007200",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Template with Fields": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments: []string{`field1==value1
+		field 2==value 2`},
+			ExpectedFields: []slack.AttachmentField{
+				{
+					Title: "field1",
+					Value: "value1",
+					Short: true,
+				},
+				{
+					Title: "field 2",
+					Value: "value 2",
+					Short: true,
+				},
+			},
+			ExpectedError: "",
+		},
+		"Custom Attachments File": {
+			Channel:         "self",
+			AttachmentsFile: "attachments.json",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+		},
+		"Custom Attachments File with Fields": {
+			Channel:         "self",
+			AttachmentsFile: "attachments.json",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments: []string{`field1==value1
+		field 2==value 2`},
+			ExpectedFields: []slack.AttachmentField{
+				{
+					Title: "field1",
+					Value: "value1",
+					Short: true,
+				},
+				{
+					Title: "field 2",
+					Value: "value 2",
+					Short: true,
+				},
+			},
+			ExpectedError: "",
+		},
+		"Template File": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			TemplateFile:    "message.tmpl",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Template File and Attachments File": {
+			Channel:         "self",
+			AttachmentsFile: "attachments.json",
+			TemplateFile:    "message.tmpl",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'ATTACHMENTS_FILE' and 'TEMPLATE_FILE' are mutually exclusive",
+		},
+		"Missing Channel": {
+			Channel:         "",
+			AttachmentsFile: "attachments.json",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "missing Slack channel",
+		},
+		"Missing Token": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "missing Slack token",
+		},
+		"Block Kit": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			BlockKit:        "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Invalid Block Kit": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			BlockKit:        "yes please",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "error parsing env.var 'BLOCK_KIT': strconv.ParseBool: parsing \"yes please\": invalid syntax",
+		},
+		"Thread Reply": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			ThreadTimestamp: "1589146397.007200",
+			Broadcast:       "true",
+			ExpectedThread:  map[string]string{"self": "1589146397.007200"},
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Reply in Thread of Timestamp File": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   true,
+			Timestamp:       "1589146397.007200",
+			ReplyInThread:   "true",
+			ExpectedThread:  map[string]string{"self": "1589146397.007200"},
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Invalid Reply in Thread": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			ReplyInThread:   "sure",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "error parsing env.var 'REPLY_IN_THREAD': strconv.ParseBool: parsing \"sure\": invalid syntax",
+		},
+		"Multiple Channels": {
+			Channel:         "C0001, C0002\nC0003,C0001",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   true,
+			Timestamp:       `{"C0001":"1589146397.007200","C0002":"1589146398.007200"}`,
+			Arguments:       []string{},
+			ExpectedChannel: []string{"C0001", "C0002", "C0003"},
+			ExpectedTs:      map[string]string{"C0001": "1589146397.007200", "C0002": "1589146398.007200"},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Ambiguous Timestamp": {
+			Channel:         "C0001,C0002",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   true,
+			Timestamp:       "1589146397.007200",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "error parsing timestamp: timestamp '1589146397.007200' is ambiguous for 2 channels, provide a JSON object of channel to timestamp",
+		},
+		"Retry Policy": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			RetryAttempts:   "3",
+			RetryDelay:      "100ms",
+			Timeout:         "2m",
+			Arguments:       []string{},
+			ExpectedRetry:   app.RetryPolicy{Attempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 30 * time.Second},
+			ExpectedTimeout: 2 * time.Minute,
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Invalid Retry Attempts": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			RetryAttempts:   "0",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "env.var 'RETRY_ATTEMPTS' should be a positive number",
+		},
+		"Zero Retry Delay": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			RetryDelay:      "0s",
+			Arguments:       []string{},
+			ExpectedRetry:   app.RetryPolicy{Attempts: 5, BaseDelay: 0, MaxDelay: 30 * time.Second},
+			ExpectedTimeout: 30 * time.Second,
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Negative Retry Delay": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			RetryDelay:      "-1s",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "env.var 'RETRY_DELAY' should not be a negative duration",
+		},
+		"Negative Retry Max Delay": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			RetryMaxDelay:   "-1s",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "env.var 'RETRY_MAX_DELAY' should not be a negative duration",
+		},
+		"Invalid Timeout": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Timeout:         "30",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "error parsing env.var 'TIMEOUT': time: missing unit in duration \"30\"",
+		},
+		"Zero Timeout": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Timeout:         "0s",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "env.var 'TIMEOUT' should be a positive duration",
+		},
+		"Negative Timeout": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Timeout:         "-1m",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "env.var 'TIMEOUT' should be a positive duration",
+		},
+		"Webhook": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedChannel: []string{""},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Webhook and Token": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'TOKEN' and 'WEBHOOK_URL' are mutually exclusive",
+		},
+		"Webhook with Timestamp File": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   true,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "updating messages via 'TIMESTAMP'/'TIMESTAMP_FILE' is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Webhook with Multiple Channels": {
+			Channel:         "C0001,C0002",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "notifying multiple channels is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Webhook with Thread": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "",
+			ThreadTimestamp: "1589146397.007200",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "replying in threads is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Delete": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "1589146397.007200",
+			Delete:          "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Delete Timestamp File": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   true,
+			Timestamp:       "",
+			Delete:          "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Delete without Timestamp": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Delete:          "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'DELETE' requires either 'TIMESTAMP', 'TIMESTAMP_FILE' or 'SCHEDULED_MESSAGE_ID'",
+		},
+		"Delete Scheduled Message": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Delete:          "true",
+			ScheduledID:     "Q1298393284",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Post At": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			PostAt:          "2099-01-01T10:00:00Z",
+			Arguments:       []string{},
+			ExpectedPostAt:  time.Date(2099, 1, 1, 10, 0, 0, 0, time.UTC),
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Post At in the Past": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			PostAt:          "2000-01-01T10:00:00Z",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "error parsing env.var 'POST_AT': '2000-01-01T10:00:00Z' is not in the future",
+		},
+		"Post At Update": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "1589146397.007200",
+			PostAt:          "1h",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'POST_AT' cannot be used to update a message",
+		},
+		"Webhook with Post At": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "",
+			PostAt:          "1h",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "scheduling messages is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Ephemeral": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			EphemeralUser:   "U123",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Ephemeral Update": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "1589146397.007200",
+			EphemeralUser:   "U123",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'EPHEMERAL_USER' cannot be used to update a message, ephemeral messages can not be updated",
+		},
+		"Webhook with Ephemeral": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "",
+			EphemeralUser:   "U123",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "sending ephemeral messages is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Users File": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Users:           "octocat: U123\nMonaLisa: U456\n",
+			LookupByEmail:   "true",
+			Arguments:       []string{},
+			ExpectedUsers:   app.Users{"octocat": "U123", "monalisa": "U456"},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Webhook with Lookup by Email": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "",
+			LookupByEmail:   "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "looking up users by email is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Find Run Message": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			FindRunMessage:  "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Find Run Message with Post At": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			PostAt:          "1h",
+			FindRunMessage:  "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'FIND_RUN_MESSAGE' and 'POST_AT' are mutually exclusive",
+		},
+		"Find Run Message with Ephemeral User": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			EphemeralUser:   "U123",
+			FindRunMessage:  "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'FIND_RUN_MESSAGE' and 'EPHEMERAL_USER' are mutually exclusive",
+		},
+		"History": {
 			Channel:         "self",
 			AttachmentsFile: "",
 			Token:           "secret-text",
 			TimestampFile:   true,
 			Timestamp:       "1589146397.007200",
+			History:         "true",
 			Arguments:       []string{},
 			ExpectedFields:  []slack.AttachmentField{},
 			ExpectedError:   "",
 		},
-		"Template with Fields": {
+		"History without Timestamp File": {
 			Channel:         "self",
 			AttachmentsFile: "",
 			Token:           "secret-text",
 			TimestampFile:   false,
 			Timestamp:       "",
-			Arguments: []string{`field1==value1
-		field 2==value 2`},
-			ExpectedFields: []slack.AttachmentField{
-				{
-					Title: "field1",
-					Value: "value1",
-					Short: true,
-				},
-				{
-					Title: "field 2",
-					Value: "value 2",
-					Short: true,
-				},
-			},
-			ExpectedError: "",
+			History:         "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'HISTORY' requires 'TIMESTAMP_FILE'",
 		},
-		"Custom Attachments File": {
+		"Stages without Timestamp File": {
 			Channel:         "self",
-			AttachmentsFile: "attachments.json",
+			AttachmentsFile: "",
 			Token:           "secret-text",
 			TimestampFile:   false,
 			Timestamp:       "",
+			Stages:          "lint, test, build",
 			Arguments:       []string{},
 			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "'STAGES' and 'STAGE' require 'TIMESTAMP_FILE'",
 		},
-		"Custom Attachments File with Fields": {
+		"Undeclared Stage": {
 			Channel:         "self",
-			AttachmentsFile: "attachments.json",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   true,
+			Timestamp:       "1589146397.007200",
+			Stages:          "lint, test",
+			Stage:           "deploy",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "stage 'deploy' is not declared in 'STAGES'",
+		},
+		"Invalid Stage Status": {
+			Channel:         "self",
+			AttachmentsFile: "",
 			Token:           "secret-text",
+			TimestampFile:   true,
+			Timestamp:       "1589146397.007200",
+			Stages:          "lint, test",
+			Stage:           "lint",
+			StageStatus:     "passed",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "invalid stage status 'passed', should be one of: running, done, failed, skipped",
+		},
+		"Webhook with Find Run Message": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
 			TimestampFile:   false,
 			Timestamp:       "",
-			Arguments: []string{`field1==value1
-		field 2==value 2`},
-			ExpectedFields: []slack.AttachmentField{
-				{
-					Title: "field1",
-					Value: "value1",
-					Short: true,
-				},
-				{
-					Title: "field 2",
-					Value: "value 2",
-					Short: true,
-				},
-			},
-			ExpectedError: "",
+			FindRunMessage:  "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "finding messages of a run is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
 		},
-		"Missing Channel": {
+		"Webhook with Delete": {
 			Channel:         "",
-			AttachmentsFile: "attachments.json",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "1589146397.007200",
+			Delete:          "true",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "deleting messages is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Webhook with Files": {
+			Channel:         "",
+			AttachmentsFile: "",
+			Token:           "",
+			WebhookURL:      "https://hooks.slack.com/services/T000/B000/XXX",
+			TimestampFile:   false,
+			Timestamp:       "",
+			Files:           "reports/*.xml",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "uploading files is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Files": {
+			Channel:         "self",
+			AttachmentsFile: "",
 			Token:           "secret-text",
 			TimestampFile:   false,
 			Timestamp:       "",
+			Files:           "reports/*.xml,\ncoverage.txt\n",
 			Arguments:       []string{},
+			ExpectedFiles:   []string{"reports/*.xml", "coverage.txt"},
 			ExpectedFields:  []slack.AttachmentField{},
-			ExpectedError:   "missing Slack channel",
+			ExpectedError:   "",
 		},
-		"Missing Token": {
+		"Log File": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			LogFile:         "test.log",
+			Arguments:       []string{},
+			ExpectedLogTail: app.DefaultLogTail,
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Log Tail": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			LogFile:         "test.log",
+			LogTail:         "10",
+			Arguments:       []string{},
+			ExpectedLogTail: 10,
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
+		},
+		"Log Tail without Log File": {
+			Channel:         "self",
+			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			LogTail:         "10",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "env.var 'LOG_TAIL' requires 'LOG_FILE'",
+		},
+		"Invalid Log Tail": {
 			Channel:         "self",
 			AttachmentsFile: "",
+			Token:           "secret-text",
+			TimestampFile:   false,
+			Timestamp:       "",
+			LogFile:         "test.log",
+			LogTail:         "0",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "env.var 'LOG_TAIL' should be a positive number",
+		},
+		"Dry Run": {
+			Channel:         "",
+			AttachmentsFile: "",
 			Token:           "",
+			DryRun:          "true",
 			TimestampFile:   false,
 			Timestamp:       "",
 			Arguments:       []string{},
+			ExpectedChannel: []string{""},
 			ExpectedFields:  []slack.AttachmentField{},
-			ExpectedError:   "missing Slack token",
+			ExpectedError:   "",
+		},
+		"Dry Run with Channel": {
+			Channel:         "self",
+			AttachmentsFile: "attachments.json",
+			Token:           "secret-text",
+			DryRun:          "1",
+			TimestampFile:   true,
+			Timestamp:       "1589146397.007200",
+			Arguments:       []string{},
+			ExpectedFields:  []slack.AttachmentField{},
+			ExpectedError:   "",
 		},
 		"Arguments": {
 			Channel:         "self",
@@ -156,10 +811,115 @@ func TestGetConfig(t *testing.T) {
 		assert.Equal(nil, err, "preparation: error setting env.var 'ATTACHMENTS_FILE'")
 		defer os.Unsetenv("ATTACHMENTS_FILE")
 
+		err = os.Setenv("TEMPLATE_FILE", test.TemplateFile)
+		assert.Equal(nil, err, "preparation: error setting env.var 'TEMPLATE_FILE'")
+		defer os.Unsetenv("TEMPLATE_FILE")
+
 		err = os.Setenv("TOKEN", test.Token)
 		assert.Equal(nil, err, "preparation: error setting env.var 'TOKEN'")
 		defer os.Unsetenv("TOKEN")
 
+		err = os.Setenv("WEBHOOK_URL", test.WebhookURL)
+		assert.Equal(nil, err, "preparation: error setting env.var 'WEBHOOK_URL'")
+		defer os.Unsetenv("WEBHOOK_URL")
+
+		err = os.Setenv("DRY_RUN", test.DryRun)
+		assert.Equal(nil, err, "preparation: error setting env.var 'DRY_RUN'")
+		defer os.Unsetenv("DRY_RUN")
+
+		err = os.Setenv("BLOCK_KIT", test.BlockKit)
+		assert.Equal(nil, err, "preparation: error setting env.var 'BLOCK_KIT'")
+		defer os.Unsetenv("BLOCK_KIT")
+
+		err = os.Setenv("THREAD_TS", test.ThreadTimestamp)
+		assert.Equal(nil, err, "preparation: error setting env.var 'THREAD_TS'")
+		defer os.Unsetenv("THREAD_TS")
+
+		err = os.Setenv("REPLY_IN_THREAD", test.ReplyInThread)
+		assert.Equal(nil, err, "preparation: error setting env.var 'REPLY_IN_THREAD'")
+		defer os.Unsetenv("REPLY_IN_THREAD")
+
+		err = os.Setenv("BROADCAST_ON_FAILURE", test.Broadcast)
+		assert.Equal(nil, err, "preparation: error setting env.var 'BROADCAST_ON_FAILURE'")
+		defer os.Unsetenv("BROADCAST_ON_FAILURE")
+
+		err = os.Setenv("RETRY_ATTEMPTS", test.RetryAttempts)
+		assert.Equal(nil, err, "preparation: error setting env.var 'RETRY_ATTEMPTS'")
+		defer os.Unsetenv("RETRY_ATTEMPTS")
+
+		err = os.Setenv("RETRY_DELAY", test.RetryDelay)
+		assert.Equal(nil, err, "preparation: error setting env.var 'RETRY_DELAY'")
+		defer os.Unsetenv("RETRY_DELAY")
+
+		err = os.Setenv("RETRY_MAX_DELAY", test.RetryMaxDelay)
+		assert.Equal(nil, err, "preparation: error setting env.var 'RETRY_MAX_DELAY'")
+		defer os.Unsetenv("RETRY_MAX_DELAY")
+
+		err = os.Setenv("TIMEOUT", test.Timeout)
+		assert.Equal(nil, err, "preparation: error setting env.var 'TIMEOUT'")
+		defer os.Unsetenv("TIMEOUT")
+
+		err = os.Setenv("DELETE", test.Delete)
+		assert.Equal(nil, err, "preparation: error setting env.var 'DELETE'")
+		defer os.Unsetenv("DELETE")
+
+		err = os.Setenv("POST_AT", test.PostAt)
+		assert.Equal(nil, err, "preparation: error setting env.var 'POST_AT'")
+		defer os.Unsetenv("POST_AT")
+
+		err = os.Setenv("SCHEDULED_MESSAGE_ID", test.ScheduledID)
+		assert.Equal(nil, err, "preparation: error setting env.var 'SCHEDULED_MESSAGE_ID'")
+		defer os.Unsetenv("SCHEDULED_MESSAGE_ID")
+
+		err = os.Setenv("EPHEMERAL_USER", test.EphemeralUser)
+		assert.Equal(nil, err, "preparation: error setting env.var 'EPHEMERAL_USER'")
+		defer os.Unsetenv("EPHEMERAL_USER")
+
+		err = os.Setenv("LOOKUP_BY_EMAIL", test.LookupByEmail)
+		assert.Equal(nil, err, "preparation: error setting env.var 'LOOKUP_BY_EMAIL'")
+		defer os.Unsetenv("LOOKUP_BY_EMAIL")
+
+		err = os.Setenv("FIND_RUN_MESSAGE", test.FindRunMessage)
+		assert.Equal(nil, err, "preparation: error setting env.var 'FIND_RUN_MESSAGE'")
+		defer os.Unsetenv("FIND_RUN_MESSAGE")
+
+		err = os.Setenv("HISTORY", test.History)
+		assert.Equal(nil, err, "preparation: error setting env.var 'HISTORY'")
+		defer os.Unsetenv("HISTORY")
+
+		for k, v := range map[string]string{"STAGES": test.Stages, "STAGE": test.Stage, "STAGE_STATUS": test.StageStatus} {
+			err = os.Setenv(k, v)
+			assert.Equal(nil, err, fmt.Sprintf("preparation: error setting env.var '%s'", k))
+			defer os.Unsetenv(k)
+		}
+
+		if test.Users != "" {
+			users, err := os.CreateTemp(os.TempDir(), "test-")
+			assert.Equal(nil, err, "preparation: error creating temporary file")
+			defer os.Remove(users.Name())
+
+			err = os.WriteFile(users.Name(), []byte(test.Users), 0644)
+			assert.Equal(nil, err, "preparation: error writing users file")
+
+			err = os.Setenv("USERS_FILE", users.Name())
+			assert.Equal(nil, err, "preparation: error setting env.var 'USERS_FILE'")
+		} else {
+			os.Unsetenv("USERS_FILE")
+		}
+		defer os.Unsetenv("USERS_FILE")
+
+		err = os.Setenv("FILES", test.Files)
+		assert.Equal(nil, err, "preparation: error setting env.var 'FILES'")
+		defer os.Unsetenv("FILES")
+
+		err = os.Setenv("LOG_FILE", test.LogFile)
+		assert.Equal(nil, err, "preparation: error setting env.var 'LOG_FILE'")
+		defer os.Unsetenv("LOG_FILE")
+
+		err = os.Setenv("LOG_TAIL", test.LogTail)
+		assert.Equal(nil, err, "preparation: error setting env.var 'LOG_TAIL'")
+		defer os.Unsetenv("LOG_TAIL")
+
 		var file string
 		if test.TimestampFile {
 			dir, err := os.MkdirTemp(".", "unittests")
@@ -178,6 +938,10 @@ func TestGetConfig(t *testing.T) {
 			err = os.Setenv("TIMESTAMP_FILE", file)
 			assert.Equal(nil, err, "preparation: error setting env.var 'TIMESTAMP_FILE'")
 			defer os.Unsetenv("TIMESTAMP_FILE")
+		} else {
+			err = os.Setenv("TIMESTAMP", test.Timestamp)
+			assert.Equal(nil, err, "preparation: error setting env.var 'TIMESTAMP'")
+			defer os.Unsetenv("TIMESTAMP")
 		}
 
 		conf, err := app.GetConfig(test.Arguments)
@@ -186,14 +950,87 @@ func TestGetConfig(t *testing.T) {
 			assert.EqualError(err, test.ExpectedError)
 		} else {
 			assert.Equal(nil, err)
+			assert.NotNil(conf.Client)
+
+			if test.WebhookURL != "" {
+				assert.Equal(&app.Webhook{URL: test.WebhookURL}, conf.Client)
+			}
+
+			if test.DryRun != "" {
+				assert.IsType(&app.DryRun{}, conf.Client)
+			}
+
+			if test.ExpectedChannel == nil {
+				test.ExpectedChannel = []string{test.Channel}
+			}
+
+			if test.ExpectedTs == nil {
+				test.ExpectedTs = map[string]string{}
+				if test.Timestamp != "" {
+					test.ExpectedTs[test.Channel] = test.Timestamp
+				}
+			}
+
+			var state *app.State
+			if test.TimestampFile {
+				state = app.NewState()
+				for channel, ts := range test.ExpectedTs {
+					state.Messages[channel] = app.Message{Channel: channel, Timestamp: ts}
+				}
+			}
+
+			if test.ExpectedRetry == (app.RetryPolicy{}) {
+				test.ExpectedRetry = app.DefaultRetryPolicy
+			}
+
+			if test.ExpectedTimeout == 0 {
+				test.ExpectedTimeout = 30 * time.Second
+			}
+
+			if test.ExpectedThread == nil {
+				test.ExpectedThread = map[string]string{}
+			}
+
+			scheduled := map[string]string{}
+			if test.ScheduledID != "" {
+				scheduled[test.Channel] = test.ScheduledID
+			}
+
+			if test.ExpectedUsers == nil {
+				test.ExpectedUsers = app.Users{}
+			}
+
+			if test.ExpectedFiles == nil {
+				test.ExpectedFiles = []string{}
+			}
 
 			c := app.Config{
-				Channel:         test.Channel,
-				AttachmentsFile: test.AttachmentsFile,
-				TimestampFile:   file,
-				Timestamp:       test.Timestamp,
-				Fields:          test.ExpectedFields,
-				Client:          slack.New(test.Token),
+				Channels:         test.ExpectedChannel,
+				AttachmentsFile:  test.AttachmentsFile,
+				TemplateFile:     test.TemplateFile,
+				TimestampFile:    file,
+				State:            state,
+				Timestamps:       test.ExpectedTs,
+				ThreadTimestamps: test.ExpectedThread,
+				Broadcast:        test.Broadcast == "true",
+				Fields:           test.ExpectedFields,
+				BlockKit:         test.BlockKit == "true",
+				Theme:            app.DefaultTheme,
+				Retry:            test.ExpectedRetry,
+				Timeout:          test.ExpectedTimeout,
+				DryRun:           test.DryRun != "",
+				Delete:           test.Delete == "true",
+				PostAt:           test.ExpectedPostAt,
+				ScheduledIDs:     scheduled,
+				EphemeralUser:    test.EphemeralUser,
+				Users:            test.ExpectedUsers,
+				LookupByEmail:    test.LookupByEmail == "true",
+				FindRunMessage:   test.FindRunMessage == "true",
+				History:          test.History == "true",
+				Files:            test.ExpectedFiles,
+				LogFile:          test.LogFile,
+				LogTail:          test.ExpectedLogTail,
+				Client:           conf.Client,
 			}
 
 			assert.Equal(c, *conf)
@@ -202,3 +1039,167 @@ func TestGetConfig(t *testing.T) {
 		os.Unsetenv("TIMESTAMP_FILE")
 	}
 }
+
+func TestDeleteMessages(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		DryRun           bool
+		DeleteErrors     map[string]error
+		ExpectedMessages []string
+		ExpectedRemoved  bool
+		ExpectedError    string
+	}
+
+	suite := map[string]test{
+		"All Deleted": {
+			DryRun:           false,
+			DeleteErrors:     map[string]error{},
+			ExpectedMessages: nil,
+			ExpectedRemoved:  true,
+			ExpectedError:    "",
+		},
+		"Partially Deleted": {
+			DryRun:           false,
+			DeleteErrors:     map[string]error{"C0002": slack.SlackErrorResponse{Err: "cant_delete_message"}},
+			ExpectedMessages: []string{"#ops"},
+			ExpectedRemoved:  false,
+			ExpectedError:    "channel '#ops': error deleting message: cant_delete_message",
+		},
+		"Dry Run": {
+			DryRun:           true,
+			DeleteErrors:     map[string]error{},
+			ExpectedMessages: []string{"#deploys", "#ops"},
+			ExpectedRemoved:  false,
+			ExpectedError:    "",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, err := os.MkdirTemp(os.TempDir(), "test-")
+		assert.Equal(nil, err, "preparation: error creating temporary directory")
+		defer os.RemoveAll(dir)
+
+		state := app.NewState()
+		state.Messages["#deploys"] = app.Message{Channel: "C0001", Timestamp: "1682942400.000100"}
+		state.Messages["#ops"] = app.Message{Channel: "C0002", Timestamp: "1682942400.000200"}
+
+		file := filepath.Join(dir, "notify.ts")
+		err = state.Save(file)
+		assert.Equal(nil, err, "preparation: error writing timestamp file")
+
+		m := new(mocks.Client)
+		for id, ts := range map[string]string{"C0001": "1682942400.000100", "C0002": "1682942400.000200"} {
+			m.On("DeleteMessageContext", mock.Anything, id, ts).Return(id, ts, test.DeleteErrors[id])
+		}
+
+		conf := &app.Config{
+			Channels:      []string{"#deploys", "#ops"},
+			ChannelIDs:    map[string]string{"#deploys": "C0001", "#ops": "C0002"},
+			TimestampFile: file,
+			State:         state,
+			Timestamps:    state.Timestamps(),
+			Retry:         app.RetryPolicy{Attempts: 1},
+			DryRun:        test.DryRun,
+			Client:        m,
+		}
+
+		err = app.DeleteMessages(context.Background(), conf)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		content, err := os.ReadFile(file)
+		if test.ExpectedRemoved {
+			assert.True(os.IsNotExist(err), "timestamp file should be removed")
+			continue
+		}
+
+		assert.Equal(nil, err)
+
+		result, err := app.ParseState(string(content), conf.Channels)
+		assert.Equal(nil, err)
+
+		channels := make([]string, 0)
+		for channel := range result.Messages {
+			channels = append(channels, channel)
+		}
+		assert.ElementsMatch(test.ExpectedMessages, channels)
+	}
+}
+
+func TestWriteOutputs(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary directory")
+	defer os.RemoveAll(dir)
+
+	outputs := filepath.Join(dir, "outputs")
+	os.Setenv("GITHUB_OUTPUT", outputs)
+	defer os.Unsetenv("GITHUB_OUTPUT")
+
+	state := app.NewState()
+	state.Messages["#ops"] = app.Message{Channel: "C0002", Timestamp: "1682942400.000200"}
+
+	conf := &app.Config{
+		Channels:      []string{"#deploys", "#ops"},
+		ChannelIDs:    map[string]string{"#deploys": "C0001", "#ops": "C0002"},
+		TimestampFile: filepath.Join(dir, "notify.ts"),
+		State:         state,
+		Fields:        []slack.AttachmentField{{Title: "key", Value: "value"}},
+	}
+
+	timestamps := map[string]string{"#deploys": "1682942460.000100"}
+	parents := map[string]string{"#deploys": "1682942400.000100"}
+	permalinks := map[string]string{"#deploys": "https://workspace.slack.com/archives/C0001/p1682942460000100"}
+
+	err = app.WriteOutputs(conf, timestamps, parents, permalinks)
+	assert.Equal(nil, err)
+
+	content, err := os.ReadFile(conf.TimestampFile)
+	assert.Equal(nil, err)
+
+	result, err := app.ParseState(string(content), conf.Channels)
+	assert.Equal(nil, err)
+
+	assert.Equal(map[string]app.Message{
+		"#deploys": {Channel: "C0001", Timestamp: "1682942460.000100", ThreadTimestamp: "1682942400.000100", Permalink: "https://workspace.slack.com/archives/C0001/p1682942460000100"},
+		"#ops":     {Channel: "C0002", Timestamp: "1682942400.000200"},
+	}, result.Messages, "messages of failed channels should be kept")
+	assert.Equal(1, len(result.History))
+	assert.Equal("running", result.History[0].Status)
+	assert.Equal(conf.Fields, result.Fields)
+
+	content, err = os.ReadFile(outputs)
+	assert.Equal(nil, err)
+
+	for _, output := range []string{
+		"TIMESTAMP=1682942460.000100\n",
+		`TIMESTAMPS={"#deploys":"1682942460.000100"}` + "\n",
+		"THREAD_TS=1682942400.000100\n",
+		`THREAD_TIMESTAMPS={"#deploys":"1682942400.000100"}` + "\n",
+		"PERMALINK=https://workspace.slack.com/archives/C0001/p1682942460000100\n",
+	} {
+		assert.Contains(string(content), output)
+	}
+
+	t.Log("Test Case - Dry Run")
+
+	err = os.Remove(conf.TimestampFile)
+	assert.Equal(nil, err)
+
+	conf.DryRun = true
+	err = app.WriteOutputs(conf, timestamps, parents, permalinks)
+	assert.Equal(nil, err)
+
+	_, err = os.Stat(conf.TimestampFile)
+	assert.True(os.IsNotExist(err), "dry run should not write a timestamp file")
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// MetadataEventType is an event type of a message metadata, identifying a message of a workflow run
+const MetadataEventType = "github_workflow_run"
+
+const (
+	// historyPageSize is a number of messages requested per page of a channel history
+	historyPageSize = 200
+
+	// maxHistoryPages limits a search of a message of a run to the most recent messages of a channel
+	maxHistoryPages = 5
+)
+
+// GetMetadata returns a message metadata identifying the current workflow run
+func GetMetadata() slack.SlackMetadata {
+	return slack.SlackMetadata{
+		EventType: MetadataEventType,
+		EventPayload: map[string]interface{}{
+			"repository": os.Getenv("GITHUB_REPOSITORY"),
+			"workflow":   os.Getenv("GITHUB_WORKFLOW"),
+			"run_id":     os.Getenv("GITHUB_RUN_ID"),
+		},
+	}
+}
+
+// IsRunMessage returns true when a message metadata belongs to the same workflow run
+func IsRunMessage(metadata, run slack.SlackMetadata) bool {
+	if metadata.EventType != run.EventType {
+		return false
+	}
+
+	for _, key := range []string{"repository", "workflow", "run_id"} {
+		if fmt.Sprint(metadata.EventPayload[key]) != fmt.Sprint(run.EventPayload[key]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FindRunMessage returns a timestamp of a message posted by the current workflow run, or an empty string when not found
+func (s *Slack) FindRunMessage(cli Client) (string, error) {
+	run := GetMetadata()
+
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID:          s.Channel,
+		Limit:              historyPageSize,
+		IncludeAllMetadata: true,
+	}
+
+	for page := 0; page < maxHistoryPages; page++ {
+		var history *slack.GetConversationHistoryResponse
+		err := s.Retry.Do(s.Context, func() error {
+			var err error
+			history, err = cli.GetConversationHistoryContext(s.Context, params)
+			return err
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "error reading channel history")
+		}
+
+		for _, m := range history.Messages {
+			// broadcasted thread replies are part of a channel history too
+			if m.ThreadTimestamp != "" && m.ThreadTimestamp != m.Timestamp {
+				continue
+			}
+
+			if IsRunMessage(m.Metadata, run) {
+				return m.Timestamp, nil
+			}
+		}
+
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+
+		params.Cursor = history.ResponseMetaData.NextCursor
+	}
+
+	return "", nil
+}
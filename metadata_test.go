@@ -0,0 +1,149 @@
+package main_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	app "action-notify-slack"
+
+	"action-notify-slack/mocks"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRunMessage(ts, threadTS, runID string) slack.Message {
+	m := slack.Message{}
+	m.Timestamp = ts
+	m.ThreadTimestamp = threadTS
+	m.Metadata = slack.SlackMetadata{
+		EventType: app.MetadataEventType,
+		EventPayload: map[string]interface{}{
+			"repository": "ore/proj",
+			"workflow":   "testing",
+			"run_id":     runID,
+		},
+	}
+
+	return m
+}
+
+func TestIsRunMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	run := slack.SlackMetadata{
+		EventType:    app.MetadataEventType,
+		EventPayload: map[string]interface{}{"repository": "ore/proj", "workflow": "testing", "run_id": "1"},
+	}
+
+	suite := map[string]struct {
+		Metadata       slack.SlackMetadata
+		ExpectedOutput bool
+	}{
+		"Same Run":        {Metadata: newRunMessage("", "", "1").Metadata, ExpectedOutput: true},
+		"Another Run":     {Metadata: newRunMessage("", "", "2").Metadata, ExpectedOutput: false},
+		"No Metadata":     {Metadata: slack.SlackMetadata{}, ExpectedOutput: false},
+		"Another Event":   {Metadata: slack.SlackMetadata{EventType: "deploy", EventPayload: run.EventPayload}, ExpectedOutput: false},
+		"Another Project": {Metadata: slack.SlackMetadata{EventType: app.MetadataEventType, EventPayload: map[string]interface{}{"repository": "ore/other", "workflow": "testing", "run_id": "1"}}, ExpectedOutput: false},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		assert.Equal(test.ExpectedOutput, app.IsRunMessage(test.Metadata, run))
+	}
+}
+
+func TestFindRunMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.Unsetenv("GITHUB_RUN_ID")
+
+	type test struct {
+		RunID          string
+		MockError      error
+		ExpectedOutput string
+		ExpectedCalls  int
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"First Page": {
+			RunID:          "1",
+			MockError:      nil,
+			ExpectedOutput: "1589146397.000100",
+			ExpectedCalls:  1,
+			ExpectedError:  "",
+		},
+		"Second Page": {
+			RunID:          "2",
+			MockError:      nil,
+			ExpectedOutput: "1589146397.000200",
+			ExpectedCalls:  2,
+			ExpectedError:  "",
+		},
+		"Broadcasted Reply": {
+			RunID:          "3",
+			MockError:      nil,
+			ExpectedOutput: "",
+			ExpectedCalls:  2,
+			ExpectedError:  "",
+		},
+		"Not Found": {
+			RunID:          "4",
+			MockError:      nil,
+			ExpectedOutput: "",
+			ExpectedCalls:  2,
+			ExpectedError:  "",
+		},
+		"slack.GetConversationHistoryContext Error": {
+			RunID:          "1",
+			MockError:      errors.New("reason"),
+			ExpectedOutput: "",
+			ExpectedCalls:  1,
+			ExpectedError:  "error reading channel history: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		os.Setenv("GITHUB_RUN_ID", test.RunID)
+
+		s := &app.Slack{Channel: "C0001", Context: context.Background(), Retry: app.RetryPolicy{Attempts: 1}}
+
+		first := &slack.GetConversationHistoryResponse{HasMore: true, Messages: []slack.Message{newRunMessage("1589146397.000100", "", "1")}}
+		first.ResponseMetaData.NextCursor = "page-2"
+
+		second := &slack.GetConversationHistoryResponse{Messages: []slack.Message{
+			newRunMessage("1589146397.000300", "1589146397.000000", "3"),
+			newRunMessage("1589146397.000200", "1589146397.000200", "2"),
+		}}
+
+		m := new(mocks.Client)
+		m.On("GetConversationHistoryContext", s.Context, mock.MatchedBy(func(p *slack.GetConversationHistoryParameters) bool {
+			return p.Cursor == "" && p.ChannelID == "C0001" && p.IncludeAllMetadata
+		})).Return(first, test.MockError)
+		m.On("GetConversationHistoryContext", s.Context, mock.MatchedBy(func(p *slack.GetConversationHistoryParameters) bool {
+			return p.Cursor == "page-2"
+		})).Return(second, test.MockError)
+
+		result, err := s.FindRunMessage(m)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+		m.AssertNumberOfCalls(t, "GetConversationHistoryContext", test.ExpectedCalls)
+	}
+}
@@ -14,6 +14,180 @@ type Client struct {
 	mock.Mock
 }
 
+// DeleteMessageContext provides a mock function with given fields: ctx, channel, messageTimestamp
+func (_m *Client) DeleteMessageContext(ctx context.Context, channel string, messageTimestamp string) (string, string, error) {
+	ret := _m.Called(ctx, channel, messageTimestamp)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, channel, messageTimestamp)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) string); ok {
+		r1 = rf(ctx, channel, messageTimestamp)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(ctx, channel, messageTimestamp)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteScheduledMessageContext provides a mock function with given fields: ctx, params
+func (_m *Client) DeleteScheduledMessageContext(ctx context.Context, params *slack.DeleteScheduledMessageParameters) (bool, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, *slack.DeleteScheduledMessageParameters) bool); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *slack.DeleteScheduledMessageParameters) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetConversationHistoryContext provides a mock function with given fields: ctx, params
+func (_m *Client) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *slack.GetConversationHistoryResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *slack.GetConversationHistoryParameters) *slack.GetConversationHistoryResponse); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*slack.GetConversationHistoryResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *slack.GetConversationHistoryParameters) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetConversationsContext provides a mock function with given fields: ctx, params
+func (_m *Client) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 []slack.Channel
+	if rf, ok := ret.Get(0).(func(context.Context, *slack.GetConversationsParameters) []slack.Channel); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]slack.Channel)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, *slack.GetConversationsParameters) string); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *slack.GetConversationsParameters) error); ok {
+		r2 = rf(ctx, params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPermalinkContext provides a mock function with given fields: ctx, params
+func (_m *Client) GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *slack.PermalinkParameters) string); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *slack.PermalinkParameters) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByEmailContext provides a mock function with given fields: ctx, email
+func (_m *Client) GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 *slack.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *slack.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*slack.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PostEphemeralContext provides a mock function with given fields: ctx, channelID, userID, options
+func (_m *Client) PostEphemeralContext(ctx context.Context, channelID string, userID string, options ...slack.MsgOption) (string, error) {
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, channelID, userID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...slack.MsgOption) string); ok {
+		r0 = rf(ctx, channelID, userID, options...)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ...slack.MsgOption) error); ok {
+		r1 = rf(ctx, channelID, userID, options...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PostMessageContext provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) PostMessageContext(_a0 context.Context, _a1 string, _a2 ...slack.MsgOption) (string, string, error) {
 	_va := make([]interface{}, len(_a2))
@@ -49,6 +223,41 @@ func (_m *Client) PostMessageContext(_a0 context.Context, _a1 string, _a2 ...sla
 	return r0, r1, r2
 }
 
+// ScheduleMessageContext provides a mock function with given fields: ctx, channelID, postAt, options
+func (_m *Client) ScheduleMessageContext(ctx context.Context, channelID string, postAt string, options ...slack.MsgOption) (string, string, error) {
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, channelID, postAt)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...slack.MsgOption) string); ok {
+		r0 = rf(ctx, channelID, postAt, options...)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ...slack.MsgOption) string); ok {
+		r1 = rf(ctx, channelID, postAt, options...)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, ...slack.MsgOption) error); ok {
+		r2 = rf(ctx, channelID, postAt, options...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // UpdateMessageContext provides a mock function with given fields: ctx, channelID, timestamp, options
 func (_m *Client) UpdateMessageContext(ctx context.Context, channelID string, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
 	_va := make([]interface{}, len(options))
@@ -90,3 +299,26 @@ func (_m *Client) UpdateMessageContext(ctx context.Context, channelID string, ti
 
 	return r0, r1, r2, r3
 }
+
+// UploadFileV2Context provides a mock function with given fields: ctx, params
+func (_m *Client) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *slack.FileSummary
+	if rf, ok := ret.Get(0).(func(context.Context, slack.UploadFileV2Parameters) *slack.FileSummary); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*slack.FileSummary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, slack.UploadFileV2Parameters) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// RetryPolicy represents a retry policy of Slack API calls
+type RetryPolicy struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy is used unless overridden by the user
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:  5,
+	BaseDelay: time.Second,
+	MaxDelay:  30 * time.Second,
+}
+
+// transientErrors are Slack API errors worth retrying
+var transientErrors = []string{
+	"internal_error",
+	"fatal_error",
+	"service_unavailable",
+	"request_timeout",
+	"ratelimited",
+}
+
+// Do executes a function, retrying transient errors with an exponential backoff and jitter.
+// Rate limited calls are retried after a duration requested by Slack.
+// A retry is never scheduled beyond the context deadline, in which case the last error is returned.
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= p.Attempts || !IsRetryable(err) {
+			return err
+		}
+
+		delay := p.delay(attempt, err)
+
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// delay returns a duration to wait before the next attempt
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var rl *slack.RateLimitedError
+	if errors.As(err, &rl) && rl.RetryAfter > 0 {
+		return rl.RetryAfter
+	}
+
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	backoff := p.BaseDelay << (attempt - 1)
+
+	// a backoff of late attempts overflows, in which case it is only limited by a maximum delay
+	if backoff <= 0 || backoff>>(attempt-1) != p.BaseDelay {
+		backoff = time.Duration(math.MaxInt64)
+	}
+
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+
+	// equal jitter: wait at least a half of the backoff to avoid hammering the API
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// IsRetryable returns true for rate limits, server side and network errors
+func IsRetryable(err error) bool {
+	var rl *slack.RateLimitedError
+	if errors.As(err, &rl) {
+		return true
+	}
+
+	var sc slack.StatusCodeError
+	if errors.As(err, &sc) {
+		return sc.Code >= 500 || sc.Code == 429
+	}
+
+	var se slack.SlackErrorResponse
+	if errors.As(err, &se) {
+		for _, e := range transientErrors {
+			if se.Err == e {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var ne net.Error
+	return errors.As(err, &ne)
+}
+
+// IsSlackError returns true when Slack API responded with an error of a given name
+func IsSlackError(err error, name string) bool {
+	var se slack.SlackErrorResponse
+	return errors.As(err, &se) && se.Err == name
+}
@@ -0,0 +1,148 @@
+package main_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	app "action-notify-slack"
+
+	"action-notify-slack/mocks"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRetryPolicyDo(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := app.RetryPolicy{
+		Attempts:  3,
+		BaseDelay: time.Millisecond,
+		MaxDelay:  5 * time.Millisecond,
+	}
+
+	type test struct {
+		Policy        app.RetryPolicy
+		Timeout       time.Duration
+		Errors        []error
+		ExpectedCalls int
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Success": {
+			Policy:        policy,
+			Errors:        []error{nil},
+			ExpectedCalls: 1,
+			ExpectedError: "",
+		},
+		"Rate Limited": {
+			Policy:        policy,
+			Errors:        []error{&slack.RateLimitedError{RetryAfter: time.Millisecond}, nil},
+			ExpectedCalls: 2,
+			ExpectedError: "",
+		},
+		"Server Error": {
+			Policy:        policy,
+			Errors:        []error{slack.StatusCodeError{Code: 503, Status: "503 Service Unavailable"}, slack.SlackErrorResponse{Err: "internal_error"}, nil},
+			ExpectedCalls: 3,
+			ExpectedError: "",
+		},
+		"Network Error": {
+			Policy:        policy,
+			Errors:        []error{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, nil},
+			ExpectedCalls: 2,
+			ExpectedError: "",
+		},
+		"Attempts Exhausted": {
+			Policy:        policy,
+			Errors:        []error{slack.StatusCodeError{Code: 502, Status: "502 Bad Gateway"}, slack.StatusCodeError{Code: 502, Status: "502 Bad Gateway"}, slack.StatusCodeError{Code: 502, Status: "502 Bad Gateway"}},
+			ExpectedCalls: 3,
+			ExpectedError: "slack server error: 502 Bad Gateway",
+		},
+		"Not Retryable": {
+			Policy:        policy,
+			Errors:        []error{slack.SlackErrorResponse{Err: "channel_not_found"}},
+			ExpectedCalls: 1,
+			ExpectedError: "channel_not_found",
+		},
+		"Beyond Deadline": {
+			Policy:        policy,
+			Timeout:       50 * time.Millisecond,
+			Errors:        []error{&slack.RateLimitedError{RetryAfter: time.Minute}},
+			ExpectedCalls: 1,
+			ExpectedError: "slack rate limit exceeded, retry after 1m0s",
+		},
+		"Zero Delay": {
+			Policy:        app.RetryPolicy{Attempts: 3, BaseDelay: 0, MaxDelay: 30 * time.Second},
+			Timeout:       time.Second,
+			Errors:        []error{slack.StatusCodeError{Code: 503, Status: "503 Service Unavailable"}, nil},
+			ExpectedCalls: 2,
+			ExpectedError: "",
+		},
+		"Zero Policy": {
+			Policy:        app.RetryPolicy{},
+			Errors:        []error{&slack.RateLimitedError{RetryAfter: time.Millisecond}},
+			ExpectedCalls: 1,
+			ExpectedError: "slack rate limit exceeded, retry after 1ms",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		ctx := context.Background()
+		if test.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, test.Timeout)
+			defer cancel()
+		}
+
+		var calls int
+		err := test.Policy.Do(ctx, func() error {
+			calls++
+			return test.Errors[calls-1]
+		})
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedCalls, calls)
+	}
+}
+
+func TestSendTemplateRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &app.Slack{
+		Channel: "self",
+		Context: context.Background(),
+		Retry: app.RetryPolicy{
+			Attempts:  3,
+			BaseDelay: time.Millisecond,
+			MaxDelay:  time.Millisecond,
+		},
+	}
+
+	expected := fmt.Sprint(time.Now().Unix())
+
+	m := new(mocks.Client)
+	m.On("PostMessageContext", s.Context, s.Channel, mock.AnythingOfType("slack.MsgOption")).Return("", "", &slack.RateLimitedError{RetryAfter: time.Millisecond}).Once()
+	m.On("PostMessageContext", s.Context, s.Channel, mock.AnythingOfType("slack.MsgOption")).Return("", expected, nil).Once()
+
+	result, err := s.SendTemplate(m, []slack.AttachmentField{})
+
+	assert.Equal(nil, err)
+	assert.Equal(expected, result)
+	m.AssertNumberOfCalls(t, "PostMessageContext", 2)
+}
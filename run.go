@@ -0,0 +1,443 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"action-notify-slack/internal/actions"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// DefaultUpdateInterval is an interval of updating a message while a wrapped command runs
+const DefaultUpdateInterval = 30 * time.Second
+
+const (
+	// runTail is a number of last lines of a command output included in a final message
+	runTail = 20
+
+	// maxRunOutput is a maximum length of an output field including its code fence. Block Kit renders
+	// every field as a text of a section block, which Slack limits to 2000 characters with a title
+	maxRunOutput = 1900
+
+	// tailBufferSize is a number of last bytes of a command output kept in memory
+	tailBufferSize = 64 * 1024
+)
+
+// ParseRunArgs splits arguments of 'run' mode into additional fields and a command following '--'
+func ParseRunArgs(args []string) ([]string, []string, error) {
+	for i, a := range args {
+		if a != "--" {
+			continue
+		}
+
+		if i == len(args)-1 {
+			return nil, nil, errors.New("missing a command after '--'")
+		}
+
+		return args[:i], args[i+1:], nil
+	}
+
+	return nil, nil, errors.New("usage: run [fields...] -- <command> [arguments...]")
+}
+
+// TailBuffer is a writer keeping only the last bytes written to it
+type TailBuffer struct {
+	Size int
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Write appends bytes to a buffer, discarding the oldest ones beyond its size
+func (b *TailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.Size {
+		b.buf = append([]byte{}, b.buf[len(b.buf)-b.Size:]...)
+	}
+
+	return len(p), nil
+}
+
+// Tail returns the last n lines written to a buffer
+func (b *TailBuffer) Tail(n int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines := strings.Split(strings.TrimRight(string(b.buf), "\r\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// GetExitStatus returns a status and an exit code of a finished command
+func GetExitStatus(err error, cancelled bool) (string, int) {
+	if err == nil {
+		return "success", 0
+	}
+
+	code := 1
+	if exit, ok := err.(*exec.ExitError); ok && exit.ExitCode() > 0 {
+		code = exit.ExitCode()
+	}
+
+	if cancelled {
+		return "cancelled", code
+	}
+
+	return "failure", code
+}
+
+// GetOutputFields returns an exit code and a tail of an output of a command
+func GetOutputFields(code int, output string) []slack.AttachmentField {
+	fields := []slack.AttachmentField{{Title: "Exit Code", Value: strconv.Itoa(code), Short: true}}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return fields
+	}
+
+	output = tailEscaped(output, maxRunOutput-len("```\n\n```"))
+
+	return append(fields, slack.AttachmentField{Title: "Output", Value: fmt.Sprintf("```\n%s\n```", output), Short: false})
+}
+
+// tailEscaped returns an escaped tail of a text which fits into a number of characters. A text is cut
+// by characters before escaping, so neither a multibyte character nor an escaped one is ever split
+func tailEscaped(s string, length int) string {
+	if utf8.RuneCountInString(escape(s)) <= length {
+		return escape(s)
+	}
+
+	r := []rune(s)
+
+	// a cut text is prefixed by an ellipsis
+	size, start := 1, len(r)
+	for start > 0 {
+		n := utf8.RuneCountInString(escape(string(r[start-1])))
+		if size+n > length {
+			break
+		}
+
+		size += n
+		start--
+	}
+
+	return "…" + escape(string(r[start:]))
+}
+
+// Run wraps a command with notifications: a running message is posted, updated with elapsed time while
+// the command runs, and completed with a status according to an exit code of the command.
+// Returns an exit code of the command, failed notifications are reported as warnings.
+func Run(args []string) int {
+	fields, command, err := ParseRunArgs(args)
+	if err != nil {
+		actions.Error(err.Error())
+		return 1
+	}
+
+	actions.AddMask(os.Getenv("TOKEN"))
+	actions.AddMask(os.Getenv("WEBHOOK_URL"))
+
+	conf, interval, err := getRunConfig(fields)
+	if err != nil {
+		// notifications never fail a command, which runs without them
+		actions.Warning(fmt.Sprintf("%s, running a command without notifications", err))
+	}
+
+	return RunCommand(conf, interval, command)
+}
+
+// getRunConfig returns a configuration of notifications about a command and an interval of their updates
+func getRunConfig(fields []string) (*Config, time.Duration, error) {
+	if err := requireEnv("GITHUB_ACTOR", "GITHUB_REPOSITORY", "GITHUB_WORKFLOW"); err != nil {
+		return nil, DefaultUpdateInterval, err
+	}
+
+	// a status is reported according to the command
+	os.Setenv("STATUS", "running")
+	os.Unsetenv("FAIL")
+
+	conf, err := GetConfig(fields)
+	if err != nil {
+		return nil, DefaultUpdateInterval, err
+	}
+
+	if err := validateRun(conf); err != nil {
+		return nil, DefaultUpdateInterval, err
+	}
+
+	interval, err := getDuration("UPDATE_INTERVAL", DefaultUpdateInterval)
+	if err == nil && interval <= 0 {
+		err = errors.New("env.var 'UPDATE_INTERVAL' should be a positive duration")
+	}
+
+	if err != nil {
+		return nil, DefaultUpdateInterval, err
+	}
+
+	return conf, interval, nil
+}
+
+// RunCommand runs a command, notifying about it every interval and once it exits, and returns its exit code.
+// A command runs without notifications when a configuration is nil.
+func RunCommand(conf *Config, interval time.Duration, command []string) int {
+	r := newRunner(conf)
+	r.notify(false)
+
+	output := &TailBuffer{Size: tailBufferSize}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.MultiWriter(os.Stdout, output)
+	cmd.Stderr = io.MultiWriter(os.Stderr, output)
+
+	if err := cmd.Start(); err != nil {
+		err = errors.Wrap(err, fmt.Sprintf("error starting command '%s'", command[0]))
+		actions.Error(err.Error())
+
+		r.finish("failure", GetOutputFields(127, err.Error()))
+
+		return 127
+	}
+
+	// a cancelled workflow interrupts the action, so the command is given a chance to stop gracefully
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var cancelled bool
+	for {
+		select {
+		case <-ticker.C:
+			r.notify(false)
+		case sig := <-signals:
+			cancelled = true
+			_ = cmd.Process.Signal(sig)
+		case err := <-done:
+			status, code := GetExitStatus(err, cancelled)
+			r.finish(status, GetOutputFields(code, output.Tail(runTail)))
+
+			return code
+		}
+	}
+}
+
+// validateRun returns an error of a configuration which can not follow a command
+func validateRun(conf *Config) error {
+	if _, ok := conf.Client.(*Webhook); ok {
+		return ErrWebhookUnsupported("wrapping a command")
+	}
+
+	switch {
+	case conf.Delete:
+		return errors.New("'DELETE' can not be used to wrap a command")
+	case !conf.PostAt.IsZero():
+		return errors.New("'POST_AT' can not be used to wrap a command")
+	case conf.EphemeralUser != "":
+		return errors.New("'EPHEMERAL_USER' can not be used to wrap a command, ephemeral messages can not be updated")
+	}
+
+	return nil
+}
+
+// runner notifies channels about a wrapped command, updating the same messages on every notification
+type runner struct {
+	conf       *Config
+	fields     []slack.AttachmentField
+	actorID    string
+	startedAt  map[string]time.Time
+	timestamps map[string]string
+	permalinks map[string]string
+}
+
+// newRunner resolves channels and a Slack user of an initiator, failures are reported as warnings
+func newRunner(conf *Config) *runner {
+	r := &runner{
+		conf:       conf,
+		startedAt:  make(map[string]time.Time),
+		timestamps: make(map[string]string),
+		permalinks: make(map[string]string),
+	}
+
+	// notifications are not configured
+	if conf == nil {
+		return r
+	}
+
+	r.fields = conf.Fields
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.Timeout)
+	defer cancel()
+
+	if err := conf.resolveChannels(ctx); err != nil {
+		actions.Warning(err.Error())
+		return r
+	}
+
+	if conf.FindRunMessage {
+		conf.findRunMessages(ctx)
+	}
+
+	r.actorID = conf.getActorSlackID(ctx)
+
+	now := time.Now().UTC()
+	for _, channel := range conf.Channels {
+		r.startedAt[channel] = conf.startedAt(channel)
+		if r.startedAt[channel].IsZero() {
+			r.startedAt[channel] = now
+		}
+	}
+
+	return r
+}
+
+// notify sends a current status to every channel. A message is posted once, and updated afterwards.
+// Files are uploaded and a thread permalink is retrieved with a final status only.
+func (r *runner) notify(final bool) {
+	// notifications are not configured, or channels are not resolved
+	if r.conf == nil || r.conf.ChannelIDs == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.conf.Timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	refreshed := make(map[string]string)
+
+	timestamps, err := FanOut(r.conf.Channels, func(channel string) (string, error) {
+		s := Slack{
+			Channel:         r.conf.ChannelIDs[channel],
+			Context:         ctx,
+			Timestamp:       r.conf.Timestamps[channel],
+			ThreadTimestamp: r.conf.ThreadTimestamps[channel],
+			Broadcast:       r.conf.Broadcast,
+			BlockKit:        r.conf.BlockKit,
+			Theme:           r.conf.Theme,
+			Retry:           r.conf.Retry,
+			ActorSlackID:    r.actorID,
+			StartedAt:       r.startedAt[channel],
+			History:         r.conf.history(),
+			Stages:          r.conf.stages(),
+		}
+
+		// a message, or a reply, of a command is already posted
+		if ts := r.timestamps[channel]; ts != "" {
+			s.Timestamp = ts
+			s.ThreadTimestamp = ""
+		}
+
+		ts, err := r.conf.sendRefreshing(&s, channel)
+		if s.Channel != r.conf.ChannelIDs[channel] {
+			mu.Lock()
+			refreshed[channel] = s.Channel
+			mu.Unlock()
+		}
+
+		if err != nil || !final {
+			return ts, err
+		}
+
+		parent := r.conf.ThreadTimestamps[channel]
+
+		// files are attached to a thread of a reply's parent
+		s.ThreadTimestamp = parent
+		if err := r.conf.upload(&s, ts); err != nil {
+			actions.Warning(fmt.Sprintf("channel '%s': %s", channel, err))
+		}
+
+		if parent == "" {
+			return ts, nil
+		}
+
+		link, err := s.GetPermalink(r.conf.Client, ts)
+		if err != nil {
+			// a reply is already delivered, a missing permalink should not lose its timestamp
+			actions.Warning(fmt.Sprintf("channel '%s': %s", channel, err))
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		r.permalinks[channel] = link
+
+		return ts, nil
+	})
+
+	if err != nil {
+		actions.Warning(err.Error())
+	}
+
+	// further notifications are sent to channels resolved again
+	for channel, id := range refreshed {
+		r.conf.ChannelIDs[channel] = id
+	}
+
+	for channel, ts := range timestamps {
+		r.timestamps[channel] = ts
+	}
+}
+
+// finish sends a final status with additional fields, marks a stage and sets step outputs
+func (r *runner) finish(status string, fields []slack.AttachmentField) {
+	os.Setenv("STATUS", status)
+
+	if r.conf == nil {
+		return
+	}
+
+	stage := strings.TrimSpace(os.Getenv("STAGE"))
+	if stage != "" && r.conf.State != nil {
+		stageStatus := StageDone
+		if status != "success" {
+			stageStatus = StageFailed
+		}
+
+		if err := r.conf.State.MarkStage(stage, stageStatus, time.Now().UTC()); err != nil {
+			actions.Warning(err.Error())
+		}
+	}
+
+	r.conf.Fields = append(append(make([]slack.AttachmentField, 0), r.fields...), fields...)
+	r.notify(true)
+
+	if len(r.timestamps) == 0 {
+		return
+	}
+
+	parents := make(map[string]string)
+	for channel := range r.timestamps {
+		if parent := r.conf.ThreadTimestamps[channel]; parent != "" {
+			parents[channel] = parent
+		}
+	}
+
+	if err := WriteOutputs(r.conf, r.timestamps, parents, r.permalinks); err != nil {
+		actions.Warning(err.Error())
+	}
+}
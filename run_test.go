@@ -0,0 +1,325 @@
+package main_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	app "action-notify-slack"
+
+	"action-notify-slack/internal/actions"
+	"action-notify-slack/mocks"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseRunArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Input           []string
+		ExpectedFields  []string
+		ExpectedCommand []string
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"Command": {
+			Input:           []string{"--", "make", "test"},
+			ExpectedFields:  []string{},
+			ExpectedCommand: []string{"make", "test"},
+			ExpectedError:   "",
+		},
+		"Fields and Command": {
+			Input:           []string{"Version==1.0.0", "--", "make", "--", "test"},
+			ExpectedFields:  []string{"Version==1.0.0"},
+			ExpectedCommand: []string{"make", "--", "test"},
+			ExpectedError:   "",
+		},
+		"Missing Separator": {
+			Input:           []string{"make", "test"},
+			ExpectedFields:  nil,
+			ExpectedCommand: nil,
+			ExpectedError:   "usage: run [fields...] -- <command> [arguments...]",
+		},
+		"Missing Command": {
+			Input:           []string{"Version==1.0.0", "--"},
+			ExpectedFields:  nil,
+			ExpectedCommand: nil,
+			ExpectedError:   "missing a command after '--'",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		fields, command, err := app.ParseRunArgs(test.Input)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		if test.ExpectedFields != nil {
+			assert.ElementsMatch(test.ExpectedFields, fields)
+		} else {
+			assert.Nil(fields)
+		}
+
+		assert.Equal(test.ExpectedCommand, command)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := &app.TailBuffer{Size: 16}
+
+	fmt.Fprint(b, "line-1\nline-2\n")
+	assert.Equal("line-1\nline-2", b.Tail(5))
+
+	fmt.Fprint(b, "line-3\nline-4\n")
+	assert.Equal("line-3\nline-4", b.Tail(2))
+	assert.Equal("2\nline-3\nline-4", b.Tail(5))
+}
+
+func TestGetExitStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	exit := exec.Command("sh", "-c", "exit 3").Run()
+
+	type test struct {
+		Error          error
+		Cancelled      bool
+		ExpectedStatus string
+		ExpectedCode   int
+	}
+
+	suite := map[string]test{
+		"Success":      {Error: nil, Cancelled: false, ExpectedStatus: "success", ExpectedCode: 0},
+		"Exit Code":    {Error: exit, Cancelled: false, ExpectedStatus: "failure", ExpectedCode: 3},
+		"Cancelled":    {Error: exit, Cancelled: true, ExpectedStatus: "cancelled", ExpectedCode: 3},
+		"Other Errors": {Error: errors.New("reason"), Cancelled: false, ExpectedStatus: "failure", ExpectedCode: 1},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		status, code := app.GetExitStatus(test.Error, test.Cancelled)
+
+		assert.Equal(test.ExpectedStatus, status)
+		assert.Equal(test.ExpectedCode, code)
+	}
+}
+
+func TestGetOutputFields(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Code           int
+		Output         string
+		ExpectedOutput []slack.AttachmentField
+	}
+
+	suite := map[string]test{
+		"No Output": {
+			Code:   0,
+			Output: "\n",
+			ExpectedOutput: []slack.AttachmentField{
+				{Title: "Exit Code", Value: "0", Short: true},
+			},
+		},
+		"Output": {
+			Code:   2,
+			Output: "expected <nil>\nFAIL",
+			ExpectedOutput: []slack.AttachmentField{
+				{Title: "Exit Code", Value: "2", Short: true},
+				{Title: "Output", Value: "```\nexpected &lt;nil&gt;\nFAIL\n```", Short: false},
+			},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		assert.Equal(test.ExpectedOutput, app.GetOutputFields(test.Code, test.Output))
+	}
+
+	t.Log("Test Case - Long Output")
+
+	fields := app.GetOutputFields(1, strings.Repeat("x", 3000))
+	assert.Equal("```\n…"+strings.Repeat("x", 1891)+"\n```", fields[1].Value)
+
+	t.Log("Test Case - Long Non-ASCII Output")
+
+	fields = app.GetOutputFields(1, "x"+strings.Repeat("é", 3000))
+	assert.True(utf8.ValidString(fields[1].Value), "output should not split a multibyte character")
+	assert.Equal("```\n…"+strings.Repeat("é", 1891)+"\n```", fields[1].Value)
+
+	t.Log("Test Case - Long Escaped Output")
+
+	fields = app.GetOutputFields(1, strings.Repeat("<x>", 1000))
+	assert.Equal("```\n…"+strings.Repeat("&lt;x&gt;", 210)+"\n```", fields[1].Value, "output should not split an escaped character")
+
+	// Slack limits a text of a section block field to 2000 characters
+	for _, block := range app.GetSectionBlocks(fields) {
+		for _, f := range block.(*slack.SectionBlock).Fields {
+			assert.LessOrEqual(utf8.RuneCountInString(f.Text), 2000)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.Setenv("STATUS", "running")
+
+	type test struct {
+		Configured      bool
+		Command         []string
+		ExpectedCode    int
+		ExpectedStatus  string
+		ExpectedPosts   int
+		ExpectedUpdates int
+	}
+
+	suite := map[string]test{
+		"Success": {
+			Configured:      true,
+			Command:         []string{"sh", "-c", "echo done"},
+			ExpectedCode:    0,
+			ExpectedStatus:  "success",
+			ExpectedPosts:   1,
+			ExpectedUpdates: 1,
+		},
+		"Exit Code": {
+			Configured:      true,
+			Command:         []string{"sh", "-c", "sleep 0.3; exit 3"},
+			ExpectedCode:    3,
+			ExpectedStatus:  "failure",
+			ExpectedPosts:   1,
+			ExpectedUpdates: 2,
+		},
+		"Command Not Found": {
+			Configured:      true,
+			Command:         []string{"missing-command-of-test"},
+			ExpectedCode:    127,
+			ExpectedStatus:  "failure",
+			ExpectedPosts:   1,
+			ExpectedUpdates: 1,
+		},
+		"Not Configured": {
+			Configured:      false,
+			Command:         []string{"sh", "-c", "exit 3"},
+			ExpectedCode:    3,
+			ExpectedStatus:  "failure",
+			ExpectedPosts:   0,
+			ExpectedUpdates: 0,
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, err := os.MkdirTemp(os.TempDir(), "test-")
+		assert.Equal(nil, err, "preparation: error creating temporary directory")
+		defer os.RemoveAll(dir)
+
+		outputs := filepath.Join(dir, "outputs")
+		os.Setenv("GITHUB_OUTPUT", outputs)
+		defer os.Unsetenv("GITHUB_OUTPUT")
+
+		os.Setenv("STATUS", "running")
+
+		m := new(mocks.Client)
+		m.On("PostMessageContext", mock.Anything, "C0001", mock.AnythingOfType("slack.MsgOption")).Return("C0001", "1682942400.000100", nil)
+		m.On("UpdateMessageContext", mock.Anything, "C0001", "1682942400.000100", mock.AnythingOfType("slack.MsgOption")).Return("C0001", "1682942400.000100", "", nil)
+
+		var conf *app.Config
+		if test.Configured {
+			conf = &app.Config{
+				Channels: []string{"C0001"},
+				Retry:    app.RetryPolicy{Attempts: 1},
+				Timeout:  time.Second,
+				Client:   m,
+			}
+		}
+
+		code := app.RunCommand(conf, 100*time.Millisecond, test.Command)
+
+		assert.Equal(test.ExpectedCode, code)
+		assert.Equal(test.ExpectedStatus, os.Getenv("STATUS"))
+		m.AssertNumberOfCalls(t, "PostMessageContext", test.ExpectedPosts)
+
+		// a message is updated on every tick while a command runs, and once it exits
+		updates := len(m.Calls) - test.ExpectedPosts
+		if test.ExpectedUpdates > 1 {
+			assert.GreaterOrEqual(updates, test.ExpectedUpdates)
+		} else {
+			assert.Equal(test.ExpectedUpdates, updates)
+		}
+
+		content, _ := os.ReadFile(outputs)
+		if test.Configured {
+			assert.Contains(string(content), "TIMESTAMP=1682942400.000100\n")
+		} else {
+			assert.Equal("", string(content))
+		}
+	}
+}
+
+func TestRunCommandSignal(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.Setenv("STATUS", "running")
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	}()
+
+	// a signal of a cancelled workflow is forwarded to a command
+	code := app.RunCommand(nil, time.Minute, []string{"sh", "-c", "trap 'kill $!; exit 143' TERM; sleep 5 & wait"})
+
+	assert.NotEqual(0, code)
+	assert.Equal("cancelled", os.Getenv("STATUS"))
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.Setenv("STATUS", "running")
+
+	var out bytes.Buffer
+	actions.Stdout = &out
+	defer func() { actions.Stdout = os.Stdout }()
+
+	os.Unsetenv("TOKEN")
+	os.Unsetenv("WEBHOOK_URL")
+
+	// notifications are not configured, yet the command runs
+	code := app.Run([]string{"--", "sh", "-c", "exit 3"})
+
+	assert.Equal(3, code)
+	assert.Contains(out.String(), "::warning::")
+	assert.Contains(out.String(), "running a command without notifications")
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// ParsePostAt returns a time to post a message at, from either an RFC3339 time or a duration relative to now
+func ParsePostAt(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		d, derr := time.ParseDuration(strings.TrimPrefix(s, "+"))
+		if derr != nil {
+			return time.Time{}, errors.New(fmt.Sprintf("'%s' is neither an RFC3339 time nor a duration", s))
+		}
+
+		t = now.Add(d)
+	}
+
+	if !t.After(now) {
+		return time.Time{}, errors.New(fmt.Sprintf("'%s' is not in the future", s))
+	}
+
+	return t, nil
+}
+
+// Unschedule deletes a scheduled message which was not posted yet
+func (s *Slack) Unschedule(cli Client, id string) error {
+	err := s.Retry.Do(s.Context, func() error {
+		_, err := cli.DeleteScheduledMessageContext(s.Context, &slack.DeleteScheduledMessageParameters{
+			Channel:            s.Channel,
+			ScheduledMessageID: id,
+		})
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "error deleting scheduled message")
+	}
+
+	return nil
+}
+
+func (s *Slack) schedule(cli Client, options ...slack.MsgOption) (string, error) {
+	if s.ThreadTimestamp != "" {
+		options = append(options, slack.MsgOptionTS(s.ThreadTimestamp))
+	}
+
+	var id string
+	err := s.Retry.Do(s.Context, func() error {
+		var err error
+		_, id, err = cli.ScheduleMessageContext(s.Context, s.Channel, strconv.FormatInt(s.PostAt.Unix(), 10), options...)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "error scheduling message")
+	}
+
+	return id, nil
+}
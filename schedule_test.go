@@ -0,0 +1,137 @@
+package main_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	app "action-notify-slack"
+
+	"action-notify-slack/mocks"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParsePostAt(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	type test struct {
+		Input          string
+		ExpectedOutput time.Time
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"RFC3339": {
+			Input:          "2023-05-02T09:30:00+02:00",
+			ExpectedOutput: time.Date(2023, 5, 2, 7, 30, 0, 0, time.UTC),
+			ExpectedError:  "",
+		},
+		"Duration": {
+			Input:          "90m",
+			ExpectedOutput: now.Add(90 * time.Minute),
+			ExpectedError:  "",
+		},
+		"Positive Duration": {
+			Input:          "+2h",
+			ExpectedOutput: now.Add(2 * time.Hour),
+			ExpectedError:  "",
+		},
+		"Past": {
+			Input:          "2023-05-01T11:00:00Z",
+			ExpectedOutput: time.Time{},
+			ExpectedError:  "'2023-05-01T11:00:00Z' is not in the future",
+		},
+		"Negative Duration": {
+			Input:          "-1h",
+			ExpectedOutput: time.Time{},
+			ExpectedError:  "'-1h' is not in the future",
+		},
+		"Invalid": {
+			Input:          "tomorrow",
+			ExpectedOutput: time.Time{},
+			ExpectedError:  "'tomorrow' is neither an RFC3339 time nor a duration",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		result, err := app.ParsePostAt(test.Input, now)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.True(test.ExpectedOutput.Equal(result), fmt.Sprintf("expected %v, got %v", test.ExpectedOutput, result))
+	}
+}
+
+func TestSendTemplateSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	postAt := time.Date(2099, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	type test struct {
+		MockError      error
+		ExpectedOutput string
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Schedule": {
+			MockError:      nil,
+			ExpectedOutput: "Q1298393284",
+			ExpectedError:  "",
+		},
+		"slack.ScheduleMessageContext Error": {
+			MockError:      errors.New("reason"),
+			ExpectedOutput: "",
+			ExpectedError:  "error scheduling message: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		s := &app.Slack{Channel: "self", Context: context.Background(), PostAt: postAt}
+
+		m := new(mocks.Client)
+		m.On("ScheduleMessageContext", s.Context, s.Channel, "4070944800", mock.AnythingOfType("slack.MsgOption")).Return(s.Channel, test.ExpectedOutput, test.MockError)
+
+		result, err := s.SendTemplate(m, []slack.AttachmentField{})
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+	}
+}
+
+func TestUnschedule(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &app.Slack{Channel: "self", Context: context.Background()}
+
+	m := new(mocks.Client)
+	m.On("DeleteScheduledMessageContext", s.Context, &slack.DeleteScheduledMessageParameters{Channel: "self", ScheduledMessageID: "Q1"}).Return(true, nil)
+	m.On("DeleteScheduledMessageContext", s.Context, &slack.DeleteScheduledMessageParameters{Channel: "self", ScheduledMessageID: "Q2"}).Return(false, slack.SlackErrorResponse{Err: "invalid_scheduled_message_id"})
+
+	assert.Equal(nil, s.Unschedule(m, "Q1"))
+	assert.EqualError(s.Unschedule(m, "Q2"), "error deleting scheduled message: invalid_scheduled_message_id")
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// Stage statuses
+const (
+	StagePending = "pending"
+	StageRunning = "running"
+	StageDone    = "done"
+	StageFailed  = "failed"
+	StageSkipped = "skipped"
+)
+
+// stageEmojis represents a stage status in a checklist
+var stageEmojis = map[string]string{
+	StagePending: ":white_large_square:",
+	StageRunning: ":arrows_counterclockwise:",
+	StageDone:    ":white_check_mark:",
+	StageFailed:  ":x:",
+	StageSkipped: ":fast_forward:",
+}
+
+// Stage represents a declared stage of a pipeline. Times are unset for stages which did not run
+type Stage struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ParseStages returns stage names separated by commas or new lines, ignoring duplicates
+func ParseStages(s string) []string {
+	stages := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	}) {
+		if name = strings.TrimSpace(name); name != "" && !seen[name] {
+			seen[name] = true
+			stages = append(stages, name)
+		}
+	}
+
+	return stages
+}
+
+// DeclareStages sets an order of stages, keeping a progress of stages which were declared before
+func (s *State) DeclareStages(names []string) {
+	known := make(map[string]Stage)
+	for _, stage := range s.Stages {
+		known[stage.Name] = stage
+	}
+
+	stages := make([]Stage, 0)
+	for _, name := range names {
+		stage, ok := known[name]
+		if !ok {
+			stage = Stage{Name: name, Status: StagePending}
+		}
+
+		stages = append(stages, stage)
+	}
+
+	s.Stages = stages
+}
+
+// MarkStage sets a status of a declared stage, tracking a time it was running for
+func (s *State) MarkStage(name, status string, now time.Time) error {
+	switch status {
+	case StageRunning, StageDone, StageFailed, StageSkipped:
+	default:
+		return errors.New(fmt.Sprintf("invalid stage status '%s', should be one of: %s, %s, %s, %s", status, StageRunning, StageDone, StageFailed, StageSkipped))
+	}
+
+	for i, stage := range s.Stages {
+		if stage.Name != name {
+			continue
+		}
+
+		switch status {
+		case StageRunning:
+			s.Stages[i].StartedAt = &now
+			s.Stages[i].FinishedAt = nil
+		case StageSkipped:
+			s.Stages[i].StartedAt = nil
+			s.Stages[i].FinishedAt = nil
+		default:
+			s.Stages[i].FinishedAt = &now
+		}
+
+		s.Stages[i].Status = status
+
+		return nil
+	}
+
+	return errors.New(fmt.Sprintf("stage '%s' is not declared in 'STAGES'", name))
+}
+
+// GetStagesStatus returns an overall status of stages: any failure fails all,
+// all done or skipped is a success, otherwise stages are in progress
+func GetStagesStatus(stages []Stage) string {
+	finished := 0
+
+	for _, stage := range stages {
+		switch stage.Status {
+		case StageFailed:
+			return "failure"
+		case StageDone, StageSkipped:
+			finished++
+		}
+	}
+
+	if finished == len(stages) {
+		return "success"
+	}
+
+	return "in_progress"
+}
+
+// GetStagesField returns a checklist of stages with a time spent on every stage
+func GetStagesField(stages []Stage, now time.Time) slack.AttachmentField {
+	finished := 0
+	lines := make([]string, 0)
+
+	for _, stage := range stages {
+		line := fmt.Sprintf("%s %s", stageEmojis[stage.Status], escape(stage.Name))
+
+		switch stage.Status {
+		case StageRunning:
+			if stage.StartedAt != nil {
+				line = fmt.Sprintf("%s (%s)", line, now.Sub(*stage.StartedAt).Round(time.Second))
+			}
+		case StageDone, StageFailed:
+			if stage.StartedAt != nil && stage.FinishedAt != nil {
+				line = fmt.Sprintf("%s (%s)", line, stage.FinishedAt.Sub(*stage.StartedAt).Round(time.Second))
+			}
+		}
+
+		if stage.Status == StageDone || stage.Status == StageSkipped {
+			finished++
+		}
+
+		lines = append(lines, line)
+	}
+
+	return slack.AttachmentField{
+		Title: fmt.Sprintf("Stages (%v/%v)", finished, len(stages)),
+		Value: strings.Join(lines, "\n"),
+		Short: false,
+	}
+}
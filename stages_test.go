@@ -0,0 +1,166 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+// timeRef returns a reference to a time of a stage
+func timeRef(t time.Time) *time.Time {
+	return &t
+}
+
+func TestParseStages(t *testing.T) {
+	assert := assert.New(t)
+
+	suite := map[string]struct {
+		Input          string
+		ExpectedOutput []string
+	}{
+		"Empty":      {Input: "", ExpectedOutput: []string{}},
+		"Commas":     {Input: "lint, test,build", ExpectedOutput: []string{"lint", "test", "build"}},
+		"New Lines":  {Input: "lint\nunit tests\n\ndeploy\n", ExpectedOutput: []string{"lint", "unit tests", "deploy"}},
+		"Duplicates": {Input: "lint,test,lint", ExpectedOutput: []string{"lint", "test"}},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		assert.Equal(test.ExpectedOutput, app.ParseStages(test.Input))
+	}
+}
+
+func TestMarkStage(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2020, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	state := app.NewState()
+	state.DeclareStages([]string{"lint", "test"})
+
+	assert.Equal(nil, state.MarkStage("lint", app.StageRunning, start))
+	assert.Equal(nil, state.MarkStage("lint", app.StageDone, start.Add(time.Minute)))
+	assert.EqualError(state.MarkStage("deploy", app.StageDone, start), "stage 'deploy' is not declared in 'STAGES'")
+	assert.EqualError(state.MarkStage("test", "passed", start), "invalid stage status 'passed', should be one of: running, done, failed, skipped")
+
+	// re-declaring keeps a progress of known stages
+	state.DeclareStages([]string{"lint", "test", "deploy"})
+
+	assert.Equal([]app.Stage{
+		{Name: "lint", Status: app.StageDone, StartedAt: timeRef(start), FinishedAt: timeRef(start.Add(time.Minute))},
+		{Name: "test", Status: app.StagePending},
+		{Name: "deploy", Status: app.StagePending},
+	}, state.Stages)
+}
+
+func TestGetStagesStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	suite := map[string]struct {
+		Statuses       []string
+		ExpectedOutput string
+	}{
+		"Pending":   {Statuses: []string{app.StagePending, app.StagePending}, ExpectedOutput: "in_progress"},
+		"Running":   {Statuses: []string{app.StageDone, app.StageRunning}, ExpectedOutput: "in_progress"},
+		"Failed":    {Statuses: []string{app.StageFailed, app.StageRunning}, ExpectedOutput: "failure"},
+		"Succeeded": {Statuses: []string{app.StageDone, app.StageSkipped}, ExpectedOutput: "success"},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		stages := make([]app.Stage, 0)
+		for _, s := range test.Statuses {
+			stages = append(stages, app.Stage{Name: s, Status: s})
+		}
+
+		assert.Equal(test.ExpectedOutput, app.GetStagesStatus(stages))
+	}
+}
+
+func TestGetStagesField(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2020, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	stages := []app.Stage{
+		{Name: "lint", Status: app.StageDone, StartedAt: timeRef(start), FinishedAt: timeRef(start.Add(80 * time.Second))},
+		{Name: "docs", Status: app.StageSkipped},
+		{Name: "test", Status: app.StageRunning, StartedAt: timeRef(start.Add(80 * time.Second))},
+		{Name: "build", Status: app.StageFailed, FinishedAt: timeRef(start)},
+		{Name: "deploy", Status: app.StagePending},
+	}
+
+	expected := slack.AttachmentField{
+		Title: "Stages (2/5)",
+		Value: ":white_check_mark: lint (1m20s)\n:fast_forward: docs\n:arrows_counterclockwise: test (2m0s)\n:x: build\n:white_large_square: deploy",
+		Short: false,
+	}
+
+	assert.Equal(expected, app.GetStagesField(stages, start.Add(200*time.Second)))
+}
+
+func TestSaveStages(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(file.Name())
+
+	start := time.Date(2020, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	state := app.NewState()
+	state.DeclareStages([]string{"lint", "docs", "test"})
+	assert.Equal(nil, state.MarkStage("lint", app.StageRunning, start))
+	assert.Equal(nil, state.MarkStage("lint", app.StageDone, start.Add(time.Minute)))
+	assert.Equal(nil, state.MarkStage("docs", app.StageSkipped, start))
+
+	err = state.Save(file.Name())
+	assert.Equal(nil, err)
+
+	content, err := os.ReadFile(file.Name())
+	assert.Equal(nil, err)
+
+	var raw struct {
+		Stages []map[string]interface{} `json:"stages"`
+	}
+	assert.Equal(nil, json.Unmarshal(content, &raw))
+	assert.Equal([]map[string]interface{}{
+		{"name": "lint", "status": "done", "started_at": "2020-05-10T12:00:00Z", "finished_at": "2020-05-10T12:01:00Z"},
+		{"name": "docs", "status": "skipped"},
+		{"name": "test", "status": "pending"},
+	}, raw.Stages, "times of stages which did not run should be omitted")
+
+	result, err := app.ParseState(string(content), []string{"self"})
+	assert.Equal(nil, err)
+	assert.Equal(state.Stages, result.Stages)
+}
+
+func TestParseStagesZeroTimes(t *testing.T) {
+	assert := assert.New(t)
+
+	content := `{"version": 1, "messages": {}, "stages": [
+		{"name": "docs", "status": "skipped", "started_at": "0001-01-01T00:00:00Z", "finished_at": "0001-01-01T00:00:00Z"},
+		{"name": "build", "status": "failed", "started_at": "0001-01-01T00:00:00Z", "finished_at": "2020-05-10T12:00:00Z"}
+	]}`
+
+	state, err := app.ParseState(content, []string{"self"})
+	assert.Equal(nil, err)
+
+	assert.Equal([]app.Stage{
+		{Name: "docs", Status: app.StageSkipped},
+		{Name: "build", Status: app.StageFailed, FinishedAt: timeRef(time.Date(2020, 5, 10, 12, 0, 0, 0, time.UTC))},
+	}, state.Stages)
+	assert.Equal(":fast_forward: docs\n:x: build", app.GetStagesField(state.Stages, time.Now()).Value)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// StateVersion is a version of a state file format
+const StateVersion = 1
+
+// State represents a state of notifications of a workflow run, stored in a timestamp file
+type State struct {
+	Version  int                     `json:"version"`
+	Messages map[string]Message      `json:"messages"`
+	PostedAt time.Time               `json:"posted_at"`
+	History  []StatusChange          `json:"history,omitempty"`
+	Fields   []slack.AttachmentField `json:"fields,omitempty"`
+	Stages   []Stage                 `json:"stages,omitempty"`
+}
+
+// Message represents a message sent to a channel. A channel is keyed as configured,
+// while 'Channel' holds its ID
+type Message struct {
+	Channel         string `json:"channel"`
+	Timestamp       string `json:"ts"`
+	ThreadTimestamp string `json:"thread_ts,omitempty"`
+	Permalink       string `json:"permalink,omitempty"`
+}
+
+// StatusChange represents a status reported at a certain time
+type StatusChange struct {
+	Status string    `json:"status"`
+	Failed bool      `json:"failed,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// NewState returns an empty state
+func NewState() *State {
+	return &State{
+		Version:  StateVersion,
+		Messages: make(map[string]Message),
+	}
+}
+
+// ParseState returns a state of a timestamp file. Besides the current format, a plain timestamp
+// and a JSON object of channel to timestamp of older versions are accepted
+func ParseState(content string, channels []string) (*State, error) {
+	content = strings.TrimSpace(content)
+
+	if strings.HasPrefix(content, "{") {
+		var version struct {
+			Version int `json:"version"`
+		}
+
+		if err := json.Unmarshal([]byte(content), &version); err == nil && version.Version > 0 {
+			if version.Version > StateVersion {
+				return nil, errors.New(fmt.Sprintf("unsupported state version %v, a newer version of the action is required", version.Version))
+			}
+
+			state := NewState()
+			if err := json.Unmarshal([]byte(content), state); err != nil {
+				return nil, errors.Wrap(err, "invalid state")
+			}
+
+			if state.Messages == nil {
+				state.Messages = make(map[string]Message)
+			}
+
+			// stages which did not run were stored with zero times before
+			for i, stage := range state.Stages {
+				if stage.StartedAt != nil && stage.StartedAt.IsZero() {
+					state.Stages[i].StartedAt = nil
+				}
+
+				if stage.FinishedAt != nil && stage.FinishedAt.IsZero() {
+					state.Stages[i].FinishedAt = nil
+				}
+			}
+
+			return state, nil
+		}
+	}
+
+	timestamps, err := ParseTimestamps(content, channels)
+	if err != nil {
+		return nil, err
+	}
+
+	state := NewState()
+	for channel, ts := range timestamps {
+		state.Messages[channel] = Message{Channel: channel, Timestamp: ts}
+	}
+
+	return state, nil
+}
+
+// Timestamps returns a timestamp of a message to update or reply to, per channel
+func (s *State) Timestamps() map[string]string {
+	timestamps := make(map[string]string)
+
+	for channel, m := range s.Messages {
+		// replies are kept in a thread of a parent message
+		if m.ThreadTimestamp != "" {
+			timestamps[channel] = m.ThreadTimestamp
+		} else if m.Timestamp != "" {
+			timestamps[channel] = m.Timestamp
+		}
+	}
+
+	return timestamps
+}
+
+// Record appends a status to a history, and keeps fields of the latest notification.
+// A post time missing in a state of an older version is taken from timestamps of its messages
+func (s *State) Record(status string, failed bool, fields []slack.AttachmentField, now time.Time) {
+	if s.PostedAt.IsZero() {
+		s.PostedAt = s.firstPostedAt(now)
+	}
+
+	s.History = append(s.History, StatusChange{Status: status, Failed: failed, Time: now})
+	s.Fields = fields
+}
+
+// firstPostedAt returns the earliest time messages of a state were posted at, or a current time without messages
+func (s *State) firstPostedAt(now time.Time) time.Time {
+	first := now
+
+	for _, m := range s.Messages {
+		// a parent message is posted before its replies
+		ts := m.ThreadTimestamp
+		if ts == "" {
+			ts = m.Timestamp
+		}
+
+		if t, err := ParseMessageTime(ts); err == nil && t.Before(first) {
+			first = t
+		}
+	}
+
+	return first
+}
+
+// Save writes a state into a file
+func (s *State) Save(filename string) error {
+	s.Version = StateVersion
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error encoding state")
+	}
+
+	if err := os.WriteFile(filename, b, 0644); err != nil {
+		return errors.Wrap(err, "error writing timestamp file")
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package main_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseState(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Content            string
+		Channels           []string
+		ExpectedTimestamps map[string]string
+		ExpectedError      string
+	}
+
+	suite := map[string]test{
+		"Empty": {
+			Content:            "",
+			Channels:           []string{"self"},
+			ExpectedTimestamps: map[string]string{},
+			ExpectedError:      "",
+		},
+		"Plain Timestamp": {
+			Content:            "1589146397.007200\n",
+			Channels:           []string{"self"},
+			ExpectedTimestamps: map[string]string{"self": "1589146397.007200"},
+			ExpectedError:      "",
+		},
+		"Timestamps of Channels": {
+			Content:            `{"C0001": "1589146397.007200", "C0002": "1589146398.007200"}`,
+			Channels:           []string{"C0001", "C0002"},
+			ExpectedTimestamps: map[string]string{"C0001": "1589146397.007200", "C0002": "1589146398.007200"},
+			ExpectedError:      "",
+		},
+		"State": {
+			Content: `{
+				"version": 1,
+				"messages": {
+					"#deploys": {"channel": "C0001", "ts": "1589146399.007200", "thread_ts": "1589146397.007200"},
+					"C0002": {"channel": "C0002", "ts": "1589146398.007200"}
+				}
+			}`,
+			Channels:           []string{"#deploys", "C0002"},
+			ExpectedTimestamps: map[string]string{"#deploys": "1589146397.007200", "C0002": "1589146398.007200"},
+			ExpectedError:      "",
+		},
+		"Unsupported Version": {
+			Content:            `{"version": 2, "messages": {}}`,
+			Channels:           []string{"self"},
+			ExpectedTimestamps: nil,
+			ExpectedError:      "unsupported state version 2, a newer version of the action is required",
+		},
+		"Ambiguous Timestamp": {
+			Content:            "1589146397.007200",
+			Channels:           []string{"C0001", "C0002"},
+			ExpectedTimestamps: nil,
+			ExpectedError:      "timestamp '1589146397.007200' is ambiguous for 2 channels, provide a JSON object of channel to timestamp",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		state, err := app.ParseState(test.Content, test.Channels)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+			assert.Nil(state)
+		} else {
+			assert.Equal(nil, err)
+			assert.Equal(app.StateVersion, state.Version)
+			assert.Equal(test.ExpectedTimestamps, state.Timestamps())
+		}
+	}
+}
+
+func TestStateSave(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(file.Name())
+
+	first := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(3 * time.Minute)
+
+	state := app.NewState()
+	state.Messages["self"] = app.Message{Channel: "C0001", Timestamp: "1682942400.000000", Permalink: "https://workspace.slack.com/archives/C0001/p1682942400000000"}
+	state.Record("running", false, []slack.AttachmentField{{Title: "key", Value: "value-1"}}, first)
+	state.Record("failed", true, []slack.AttachmentField{{Title: "key", Value: "value-2"}}, second)
+
+	err = state.Save(file.Name())
+	assert.Equal(nil, err)
+
+	content, err := os.ReadFile(file.Name())
+	assert.Equal(nil, err)
+
+	result, err := app.ParseState(string(content), []string{"self"})
+	assert.Equal(nil, err)
+
+	assert.Equal(state.Messages, result.Messages)
+	assert.True(first.Equal(result.PostedAt), "first post time should be kept")
+	assert.Equal([]app.StatusChange{
+		{Status: "running", Failed: false, Time: first},
+		{Status: "failed", Failed: true, Time: second},
+	}, result.History)
+	assert.Equal([]slack.AttachmentField{{Title: "key", Value: "value-2"}}, result.Fields)
+}
+
+func TestStateRecordLegacy(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(file.Name())
+
+	postedAt := time.Date(2020, 5, 10, 21, 33, 17, 7200000, time.UTC)
+	first := postedAt.Add(10 * time.Minute)
+	second := first.Add(5 * time.Minute)
+
+	state, err := app.ParseState("1589146397.007200", []string{"self"})
+	assert.Equal(nil, err, "preparation: error parsing legacy state")
+
+	for _, now := range []time.Time{first, second} {
+		state.Record("running", false, nil, now)
+
+		err = state.Save(file.Name())
+		assert.Equal(nil, err)
+
+		content, err := os.ReadFile(file.Name())
+		assert.Equal(nil, err)
+
+		state, err = app.ParseState(string(content), []string{"self"})
+		assert.Equal(nil, err)
+
+		assert.True(postedAt.Equal(state.PostedAt), "post time should be taken from a message timestamp")
+	}
+
+	assert.Equal("15m0s", app.GetTimingFields(state.PostedAt, second)[1].Value)
+}
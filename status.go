@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Result represents a result of a GitHub Actions job or step
+type Result struct {
+	Name   string
+	Result string
+	Style  Style
+}
+
+// Counts represents a number of succeeded, failed and skipped jobs or steps
+type Counts struct {
+	Succeeded int
+	Failed    int
+	Skipped   int
+}
+
+// githubContext represents a single entry of 'toJSON(steps)' or 'toJSON(needs)' GitHub context
+type githubContext struct {
+	Result     string `json:"result"`
+	Conclusion string `json:"conclusion"`
+	Outcome    string `json:"outcome"`
+}
+
+// GetStatus returns an overall status of env.var 'STATUS' and results of jobs or steps it consists of
+func GetStatus() (string, []Result, error) {
+	status, results, err := ParseStatus(os.Getenv("STATUS"))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error parsing env.var 'STATUS'")
+	}
+
+	return status, results, nil
+}
+
+// ParseStatus returns a status as is, or derives an overall status of 'toJSON(steps)' or 'toJSON(needs)' JSON
+func ParseStatus(raw string) (string, []Result, error) {
+	if !strings.HasPrefix(strings.TrimSpace(raw), "{") {
+		return raw, nil, nil
+	}
+
+	var entries map[string]githubContext
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		return "", nil, err
+	}
+
+	results := make([]Result, 0)
+	for name, e := range entries {
+		r := e.Result
+		if r == "" {
+			// 'conclusion' of a step considers 'continue-on-error', while 'outcome' does not
+			r = e.Conclusion
+		}
+
+		if r == "" {
+			r = e.Outcome
+		}
+
+		results = append(results, Result{Name: name, Result: r})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return GetOverallStatus(results), results, nil
+}
+
+// GetOverallStatus derives a single status of multiple results: any failure fails all,
+// then any cancellation cancels all, skipped only if all are skipped, otherwise a success
+func GetOverallStatus(results []Result) string {
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[strings.ToLower(r.Result)]++
+	}
+
+	switch {
+	case counts["failure"] > 0 || counts["timed_out"] > 0:
+		return "failure"
+	case counts["cancelled"] > 0:
+		return "cancelled"
+	case len(results) > 0 && counts["skipped"] == len(results):
+		return "skipped"
+	default:
+		return "success"
+	}
+}
+
+// GetCounts returns a number of succeeded, failed and skipped results
+func GetCounts(results []Result) Counts {
+	var c Counts
+
+	for _, r := range results {
+		switch {
+		case strings.EqualFold(r.Result, "success"):
+			c.Succeeded++
+		case strings.EqualFold(r.Result, "skipped"):
+			c.Skipped++
+		case r.Style.Failure:
+			c.Failed++
+		}
+	}
+
+	return c
+}
+
+// String returns a human readable summary of counts
+func (c Counts) String() string {
+	return fmt.Sprintf("%v succeeded, %v failed, %v skipped", c.Succeeded, c.Failed, c.Skipped)
+}
@@ -0,0 +1,121 @@
+package main_test
+
+import (
+	"os"
+	"testing"
+
+	app "action-notify-slack"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Input           string
+		ExpectedStatus  string
+		ExpectedResults []app.Result
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"Plain": {
+			Input:           "deploying",
+			ExpectedStatus:  "deploying",
+			ExpectedResults: nil,
+		},
+		"Steps": {
+			Input:          `{"build": {"outputs": {}, "outcome": "failure", "conclusion": "success"}, "test": {"outputs": {}, "outcome": "success", "conclusion": "success"}}`,
+			ExpectedStatus: "success",
+			ExpectedResults: []app.Result{
+				{Name: "build", Result: "success"},
+				{Name: "test", Result: "success"},
+			},
+		},
+		"Needs with Failure": {
+			Input:          `{"lint": {"result": "success", "outputs": {}}, "test": {"result": "failure", "outputs": {}}, "deploy": {"result": "cancelled", "outputs": {}}}`,
+			ExpectedStatus: "failure",
+			ExpectedResults: []app.Result{
+				{Name: "deploy", Result: "cancelled"},
+				{Name: "lint", Result: "success"},
+				{Name: "test", Result: "failure"},
+			},
+		},
+		"Needs with Cancellation": {
+			Input:          `{"lint": {"result": "success"}, "deploy": {"result": "cancelled"}}`,
+			ExpectedStatus: "cancelled",
+			ExpectedResults: []app.Result{
+				{Name: "deploy", Result: "cancelled"},
+				{Name: "lint", Result: "success"},
+			},
+		},
+		"All Skipped": {
+			Input:          `{"deploy": {"result": "skipped"}}`,
+			ExpectedStatus: "skipped",
+			ExpectedResults: []app.Result{
+				{Name: "deploy", Result: "skipped"},
+			},
+		},
+		"Partially Skipped": {
+			Input:          `{"lint": {"result": "success"}, "deploy": {"result": "skipped"}}`,
+			ExpectedStatus: "success",
+			ExpectedResults: []app.Result{
+				{Name: "deploy", Result: "skipped"},
+				{Name: "lint", Result: "success"},
+			},
+		},
+		"Invalid JSON": {
+			Input:         `{"lint": `,
+			ExpectedError: "unexpected end of JSON input",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		status, results, err := app.ParseStatus(test.Input)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		assert.Equal(nil, err)
+		assert.Equal(test.ExpectedStatus, status)
+		assert.Equal(test.ExpectedResults, results)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	os.Setenv("STATUS", `{"test": {"result": "timed_out"}}`)
+	defer os.Setenv("STATUS", "running")
+
+	status, _, err := app.GetStatus()
+	assert.Equal(nil, err)
+	assert.Equal("failure", status)
+	assert.Equal("FAILED", app.DefaultTheme.Style(status, false).Label)
+
+	os.Setenv("STATUS", `{"test": `)
+
+	_, _, err = app.GetStatus()
+	assert.EqualError(err, "error parsing env.var 'STATUS': unexpected end of JSON input")
+}
+
+func TestGetCounts(t *testing.T) {
+	assert := assert.New(t)
+
+	counts := app.GetCounts([]app.Result{
+		{Name: "lint", Result: "success"},
+		{Name: "test", Result: "failure", Style: app.Style{Failure: true}},
+		{Name: "e2e", Result: "cancelled", Style: app.Style{Failure: true}},
+		{Name: "deploy", Result: "skipped"},
+	})
+
+	assert.Equal(app.Counts{Succeeded: 1, Failed: 2, Skipped: 1}, counts)
+	assert.Equal("1 succeeded, 2 failed, 1 skipped", counts.String())
+}
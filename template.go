@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// TemplateData represents a data model available to message templates
+type TemplateData struct {
+	ServerURL    string
+	Repository   string
+	Workflow     string
+	Actor        string
+	ActorSlackID string
+	RunID        string
+	EventName    string
+	Event        *Event
+	Status       string
+	Failed       bool
+	Style        Style
+	Results      []Result
+	Fields       []slack.AttachmentField
+	StartedAt    time.Time
+	Env          map[string]string
+}
+
+// Payload represents a message rendered by a template
+type Payload struct {
+	Text        string             `json:"text,omitempty"`
+	Attachments []slack.Attachment `json:"attachments,omitempty"`
+	Blocks      slack.Blocks       `json:"blocks,omitempty"`
+}
+
+// NewTemplateData returns a data model populated from GitHub Actions environment
+func NewTemplateData(theme *Theme, status string, failed bool, fields []slack.AttachmentField) *TemplateData {
+	env := make(map[string]string)
+	for _, e := range os.Environ() {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 && strings.HasPrefix(kv[0], "GITHUB_") {
+			env[kv[0]] = kv[1]
+		}
+	}
+
+	return &TemplateData{
+		ServerURL:  GetServerURL(),
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		Workflow:   os.Getenv("GITHUB_WORKFLOW"),
+		Actor:      os.Getenv("GITHUB_ACTOR"),
+		RunID:      os.Getenv("GITHUB_RUN_ID"),
+		EventName:  os.Getenv("GITHUB_EVENT_NAME"),
+		Status:     status,
+		Failed:     failed,
+		Style:      theme.Style(status, failed),
+		Fields:     fields,
+		Env:        env,
+	}
+}
+
+// Counts returns a number of succeeded, failed and skipped jobs or steps
+func (d *TemplateData) Counts() Counts {
+	return GetCounts(d.Results)
+}
+
+// GetServerURL returns a URL of GitHub server, including an optional path prefix, without a trailing slash
+func GetServerURL() string {
+	u := strings.TrimRight(strings.TrimSpace(os.Getenv("GITHUB_SERVER_URL")), "/")
+	if u == "" {
+		return "https://github.com"
+	}
+
+	return u
+}
+
+// Label returns a display label of a status
+func (d *TemplateData) Label() string {
+	if d.Style.Label != "" {
+		return d.Style.Label
+	}
+
+	if d.Failed {
+		return "FAILED"
+	}
+
+	return strings.ToUpper(d.Status)
+}
+
+// TemplateFuncs returns helper functions available to message templates
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"truncate": truncate,
+		"link":     link,
+		"mention":  mention,
+		"duration": duration,
+		"json":     jsonEscape,
+		"escape":   escape,
+	}
+}
+
+// RenderTemplate renders a template file against the data model
+func RenderTemplate(filename string, data *TemplateData) ([]byte, error) {
+	file, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error reading file '%s'", filename))
+	}
+
+	tmpl, err := template.New(filepath.Base(filename)).Funcs(TemplateFuncs()).Option("missingkey=zero").Parse(string(file))
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error parsing template '%s'", filename))
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error rendering template '%s'", filename))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParsePayload parses a JSON containing either a single attachment, a list of attachments
+// or a message with 'attachments' and/or 'blocks', and appends additional fields to it
+func ParsePayload(data []byte, fields []slack.AttachmentField) (slack.MsgOption, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err == nil {
+		_, hasBlocks := keys["blocks"]
+		_, hasAttachments := keys["attachments"]
+
+		if hasBlocks || hasAttachments {
+			var p Payload
+			if err := json.Unmarshal(data, &p); err != nil {
+				return nil, err
+			}
+
+			options := make([]slack.MsgOption, 0)
+
+			if p.Text != "" {
+				options = append(options, slack.MsgOptionText(p.Text, false))
+			}
+
+			if len(p.Blocks.BlockSet) > 0 {
+				options = append(options, slack.MsgOptionBlocks(append(p.Blocks.BlockSet, GetSectionBlocks(fields)...)...))
+			} else {
+				for i := range p.Attachments {
+					p.Attachments[i].Fields = append(p.Attachments[i].Fields, fields...)
+				}
+			}
+
+			if len(p.Attachments) > 0 {
+				options = append(options, slack.MsgOptionAttachments(p.Attachments...))
+			}
+
+			return slack.MsgOptionCompose(options...), nil
+		}
+	}
+
+	return ParseAttachments(data, fields)
+}
+
+// ParseAttachments parses a JSON containing either a single attachment or a list of attachments,
+// and appends additional fields to every attachment
+func ParseAttachments(data []byte, fields []slack.AttachmentField) (slack.MsgOption, error) {
+	var slice []slack.Attachment
+	if err := json.Unmarshal(data, &slice); err == nil {
+		for i := range slice {
+			slice[i].Fields = append(slice[i].Fields, fields...)
+		}
+
+		return slack.MsgOptionAttachments(slice...), nil
+	}
+
+	var single slack.Attachment
+	if err := json.Unmarshal(data, &single); err != nil {
+		return nil, err
+	}
+
+	single.Fields = append(single.Fields, fields...)
+	return slack.MsgOptionAttachments(single), nil
+}
+
+// truncate shortens a string to a maximum of n characters
+func truncate(n int, s string) string {
+	r := []rune(s)
+	if n < 0 || len(r) <= n {
+		return s
+	}
+
+	if n <= 3 {
+		return string(r[:n])
+	}
+
+	return string(r[:n-3]) + "..."
+}
+
+// link returns a Slack formatted hyperlink
+func link(url, text string) string {
+	return fmt.Sprintf("<%s|%s>", url, text)
+}
+
+// mention returns a Slack formatted mention of a user, a user group or a special keyword
+func mention(id string) string {
+	switch {
+	case id == "":
+		return ""
+	case id == "here" || id == "channel" || id == "everyone":
+		return fmt.Sprintf("<!%s>", id)
+	case strings.HasPrefix(id, "S"):
+		return fmt.Sprintf("<!subteam^%s>", id)
+	default:
+		return fmt.Sprintf("<@%s>", id)
+	}
+}
+
+// duration returns a human readable duration. Accepts time.Duration,
+// time.Time or unix timestamp (elapsed time until now) and duration strings
+func duration(v interface{}) (string, error) {
+	var d time.Duration
+
+	switch t := v.(type) {
+	case time.Duration:
+		d = t
+	case time.Time:
+		d = time.Since(t)
+	case int:
+		d = time.Since(time.Unix(int64(t), 0))
+	case int64:
+		d = time.Since(time.Unix(t, 0))
+	case string:
+		if sec, err := strconv.ParseFloat(t, 64); err == nil {
+			d = time.Since(time.Unix(int64(sec), 0))
+		} else if parsed, err := time.ParseDuration(t); err == nil {
+			d = parsed
+		} else {
+			return "", errors.New(fmt.Sprintf("invalid duration '%s'", t))
+		}
+	default:
+		return "", errors.New(fmt.Sprintf("unsupported duration type %T", v))
+	}
+
+	return d.Round(time.Second).String(), nil
+}
+
+// jsonEscape escapes a string to be safely embedded in a JSON string
+func jsonEscape(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
@@ -0,0 +1,299 @@
+package main_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	app "action-notify-slack"
+
+	"action-notify-slack/mocks"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	filename, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(filename.Name())
+
+	type test struct {
+		Template       string
+		Data           *app.TemplateData
+		ExpectedOutput string
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Data Model": {
+			Template: `{{ .Repository }} {{ .Workflow }} {{ .Actor }} {{ .RunID }} {{ .Status }} {{ .Failed }} {{ .Env.GITHUB_SHA }}`,
+			Data: &app.TemplateData{
+				Repository: "org/proj",
+				Workflow:   "ci",
+				Actor:      "user",
+				RunID:      "1",
+				Status:     "running",
+				Failed:     false,
+				Env:        map[string]string{"GITHUB_SHA": "abc"},
+			},
+			ExpectedOutput: "org/proj ci user 1 running false abc",
+			ExpectedError:  "",
+		},
+		"Fields": {
+			Template: `{{ range .Fields }}{{ .Title }}={{ .Value }};{{ end }}`,
+			Data: &app.TemplateData{
+				Fields: []slack.AttachmentField{
+					{Title: "key-1", Value: "value-1"},
+					{Title: "key-2", Value: "value-2"},
+				},
+			},
+			ExpectedOutput: "key-1=value-1;key-2=value-2;",
+			ExpectedError:  "",
+		},
+		"Helpers": {
+			Template: `{{ upper .Status }} {{ .Workflow | truncate 6 }} {{ link "https://github.com" "GitHub" }} {{ mention "U123" }} {{ mention "here" }} {{ duration "90s" }} {{ json .Actor }}`,
+			Data: &app.TemplateData{
+				Status:   "running",
+				Workflow: "production",
+				Actor:    `"quoted"`,
+			},
+			ExpectedOutput: `RUNNING pro... <https://github.com|GitHub> <@U123> <!here> 1m30s \"quoted\"`,
+			ExpectedError:  "",
+		},
+		"Missing Environment Variable": {
+			Template:       `[{{ .Env.GITHUB_MISSING }}]`,
+			Data:           &app.TemplateData{Env: map[string]string{}},
+			ExpectedOutput: "[]",
+			ExpectedError:  "",
+		},
+		"Parsing Error": {
+			Template:       `{{ .Status `,
+			Data:           &app.TemplateData{},
+			ExpectedOutput: "",
+			ExpectedError:  fmt.Sprintf("error parsing template '%s'", filename.Name()),
+		},
+		"Rendering Error": {
+			Template:       `{{ duration "soon" }}`,
+			Data:           &app.TemplateData{},
+			ExpectedOutput: "",
+			ExpectedError:  fmt.Sprintf("error rendering template '%s'", filename.Name()),
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		err = os.WriteFile(filename.Name(), []byte(test.Template), 0644)
+		assert.Equal(nil, err, "preparation: error writing template to temporary file")
+
+		result, err := app.RenderTemplate(filename.Name(), test.Data)
+
+		if test.ExpectedError != "" {
+			assert.ErrorContains(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, string(result))
+	}
+}
+
+func TestSendTemplateFile(t *testing.T) {
+	assert := assert.New(t)
+
+	filename, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(filename.Name())
+
+	type test struct {
+		Receiver       *app.Slack
+		Template       string
+		ExpectedOutput string
+		MockError      error
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Attachment": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: "",
+			},
+			Template: `{
+	"color": "{{ if .Failed }}#fd0000{{ else }}#0ce823{{ end }}",
+	"fields": [
+		{{- range .Fields }}
+		{ "title": "{{ json .Title }}", "value": "{{ json .Value }}", "short": true },
+		{{- end }}
+		{ "title": "Status", "value": "{{ upper .Status }}", "short": true }
+	]
+}`,
+			ExpectedOutput: fmt.Sprint(time.Now().Unix()),
+			MockError:      nil,
+			ExpectedError:  "",
+		},
+		"Blocks": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: fmt.Sprint(time.Now().Unix()),
+			},
+			Template: `{
+	"text": "{{ .Repository }}: {{ upper .Status }}",
+	"blocks": [
+		{ "type": "section", "text": { "type": "mrkdwn", "text": "{{ link "https://github.com" .Repository }}" } }
+	]
+}`,
+			ExpectedOutput: fmt.Sprint(time.Now().Unix()),
+			MockError:      nil,
+			ExpectedError:  "",
+		},
+		"Invalid JSON": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: "",
+			},
+			Template:       `{ "color": "{{ .Status }}", }`,
+			ExpectedOutput: "",
+			MockError:      nil,
+			ExpectedError:  fmt.Sprintf("invalid JSON rendered from template '%s': invalid character '}' looking for beginning of object key string", filename.Name()),
+		},
+		"slack.PostMessageContext Error": {
+			Receiver: &app.Slack{
+				Channel:   "self",
+				Context:   context.Background(),
+				Timestamp: "",
+			},
+			Template:       `{ "color": "#0ce823" }`,
+			ExpectedOutput: "",
+			MockError:      errors.New("reason"),
+			ExpectedError:  "error sending message: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		err = os.WriteFile(filename.Name(), []byte(test.Template), 0644)
+		assert.Equal(nil, err, "preparation: error writing template to temporary file")
+
+		m := new(mocks.Client)
+
+		m.On("UpdateMessageContext", test.Receiver.Context, test.Receiver.Channel, test.Receiver.Timestamp, mock.AnythingOfType("slack.MsgOption")).Return("", test.ExpectedOutput, "", test.MockError)
+		m.On("PostMessageContext", test.Receiver.Context, test.Receiver.Channel, mock.AnythingOfType("slack.MsgOption")).Return("", test.ExpectedOutput, test.MockError)
+
+		result, err := test.Receiver.SendTemplateFile(m, filename.Name(), []slack.AttachmentField{{Title: "key", Value: `"value"`}})
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+	}
+}
+
+func TestGetServerURL(t *testing.T) {
+	assert := assert.New(t)
+
+	suite := map[string]struct {
+		Input          string
+		ExpectedOutput string
+	}{
+		"Default":        {Input: "", ExpectedOutput: "https://github.com"},
+		"GitHub":         {Input: "https://github.com", ExpectedOutput: "https://github.com"},
+		"Trailing Slash": {Input: "https://github.example.com/", ExpectedOutput: "https://github.example.com"},
+		"Path Prefix":    {Input: "https://example.com/github/", ExpectedOutput: "https://example.com/github"},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		os.Setenv("GITHUB_SERVER_URL", test.Input)
+		assert.Equal(test.ExpectedOutput, app.GetServerURL())
+	}
+
+	os.Unsetenv("GITHUB_SERVER_URL")
+}
+
+func TestParseAttachments(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Parser        func([]byte, []slack.AttachmentField) (slack.MsgOption, error)
+		Input         string
+		ExpectedKeys  []string
+		ExpectedColor string
+	}
+
+	attachment := `{"color":"#ff0000","blocks":[{"type":"section","text":{"type":"mrkdwn","text":"failed"}}]}`
+
+	suite := map[string]test{
+		"Attachment With Blocks": {
+			Parser:        app.ParseAttachments,
+			Input:         attachment,
+			ExpectedKeys:  []string{"attachments", "channel"},
+			ExpectedColor: "#ff0000",
+		},
+		"List Of Attachments": {
+			Parser:        app.ParseAttachments,
+			Input:         "[" + attachment + "]",
+			ExpectedKeys:  []string{"attachments", "channel"},
+			ExpectedColor: "#ff0000",
+		},
+		"Message With Blocks": {
+			Parser:        app.ParsePayload,
+			Input:         `{"blocks":[{"type":"section","text":{"type":"mrkdwn","text":"failed"}}]}`,
+			ExpectedKeys:  []string{"blocks", "channel"},
+			ExpectedColor: "",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		msg, err := test.Parser([]byte(test.Input), nil)
+		assert.Equal(nil, err)
+
+		b, err := app.GetPayload("chat.postMessage", "C0001", msg)
+		assert.Equal(nil, err)
+
+		var result struct {
+			Payload map[string]json.RawMessage `json:"payload"`
+		}
+		assert.Equal(nil, json.Unmarshal(b, &result))
+
+		keys := make([]string, 0)
+		for k := range result.Payload {
+			keys = append(keys, k)
+		}
+		assert.ElementsMatch(test.ExpectedKeys, keys)
+
+		if test.ExpectedColor != "" {
+			var attachments []slack.Attachment
+			assert.Equal(nil, json.Unmarshal(result.Payload["attachments"], &attachments))
+			assert.Equal(1, len(attachments))
+			assert.Equal(test.ExpectedColor, attachments[0].Color)
+			assert.Equal(1, len(attachments[0].Blocks.BlockSet))
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Style represents a visual appearance of a status
+type Style struct {
+	Color   string `json:"color" yaml:"color"`
+	Emoji   string `json:"emoji" yaml:"emoji"`
+	Label   string `json:"label" yaml:"label"`
+	Failure bool   `json:"failure" yaml:"failure"`
+}
+
+// Rule maps status keywords and/or a regular expression to a Style
+type Rule struct {
+	Style    `yaml:",inline"`
+	Statuses []string `json:"statuses" yaml:"statuses"`
+	Regex    string   `json:"regex" yaml:"regex"`
+
+	re *regexp.Regexp
+}
+
+// Theme represents a mapping of statuses to styles
+type Theme struct {
+	Rules   []Rule `json:"statuses" yaml:"statuses"`
+	Default Style  `json:"default" yaml:"default"`
+	Failure Style  `json:"failure" yaml:"failure"`
+}
+
+// DefaultTheme is used unless overridden by a theme file
+var DefaultTheme = &Theme{
+	Rules: []Rule{
+		// GitHub Actions job/step conclusions
+		{
+			Statuses: []string{"success"},
+			Style:    Style{Color: "#0ce823", Emoji: ":green_square:", Label: "SUCCEEDED"},
+		},
+		{
+			Statuses: []string{"failure"},
+			Style:    Style{Color: "#fd0000", Emoji: ":red_square:", Label: "FAILED", Failure: true},
+		},
+		{
+			Statuses: []string{"cancelled"},
+			Style:    Style{Color: "#fd0000", Emoji: ":red_square:", Label: "CANCELLED", Failure: true},
+		},
+		{
+			Statuses: []string{"timed_out"},
+			Style:    Style{Color: "#fd0000", Emoji: ":red_square:", Label: "TIMED OUT", Failure: true},
+		},
+		{
+			Statuses: []string{"skipped"},
+			Style:    Style{Color: "#777777", Emoji: ":fast_forward:", Label: "SKIPPED"},
+		},
+		{
+			Statuses: []string{"neutral"},
+			Style:    Style{Color: "#777777", Emoji: ":white_large_square:", Label: "NEUTRAL"},
+		},
+		{
+			Statuses: []string{"action_required"},
+			Style:    Style{Color: "#fda100", Emoji: ":orange_square:", Label: "ACTION REQUIRED"},
+		},
+		{
+			Statuses: []string{"queued", "pending", "waiting", "in_progress"},
+			Style:    Style{Color: "#fbf000", Emoji: ":yellow_square:", Label: "IN PROGRESS"},
+		},
+		// generic statuses
+		{
+			Statuses: []string{"running", "started", "building", "initializing"},
+			Style:    Style{Color: "#fbf000", Emoji: ":yellow_square:"},
+		},
+		{
+			Statuses: []string{"deploying", "uploading", "publishing", "creating"},
+			Style:    Style{Color: "#fda100", Emoji: ":orange_square:"},
+		},
+		{
+			Statuses: []string{"finished", "succeeded", "passed", "built", "released"},
+			Style:    Style{Color: "#0ce823", Emoji: ":green_square:"},
+		},
+		{
+			Statuses: []string{"failed", "aborted", "canceled", "terminated"},
+			Style:    Style{Color: "#fd0000", Emoji: ":red_square:", Failure: true},
+		},
+	},
+	Default: Style{Color: "#777777", Emoji: ":white_large_square:"},
+	Failure: Style{Color: "#fd0000", Emoji: ":red_square:", Failure: true},
+}
+
+// LoadTheme returns a theme read from a YAML or JSON file, on top of the default theme.
+// Rules of a file take precedence over the default ones.
+func LoadTheme(filename string) (*Theme, error) {
+	file, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error reading file '%s'", filename))
+	}
+
+	custom := new(Theme)
+	if err := yaml.Unmarshal(file, custom); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("invalid theme file '%s'", filename))
+	}
+
+	for i, r := range custom.Rules {
+		if r.Regex == "" {
+			continue
+		}
+
+		custom.Rules[i].re, err = regexp.Compile(r.Regex)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid regex '%s' in theme file '%s'", r.Regex, filename))
+		}
+	}
+
+	theme := &Theme{
+		Rules:   append(custom.Rules, DefaultTheme.Rules...),
+		Default: merge(DefaultTheme.Default, custom.Default),
+		Failure: merge(DefaultTheme.Failure, custom.Failure),
+	}
+
+	// failure style is always a failure
+	theme.Failure.Failure = true
+
+	return theme, nil
+}
+
+// Style returns a Style matching the status
+func (t *Theme) Style(status string, failed bool) Style {
+	if failed {
+		return t.Failure
+	}
+
+	for _, r := range t.Rules {
+		if r.Matches(status) {
+			return r.Style
+		}
+	}
+
+	return t.Default
+}
+
+// Matches returns true when a rule matches the status
+func (r *Rule) Matches(status string) bool {
+	for _, s := range r.Statuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+
+	return r.re != nil && r.re.MatchString(status)
+}
+
+// merge overrides non empty properties of a base style
+func merge(base, override Style) Style {
+	if override.Color != "" {
+		base.Color = override.Color
+	}
+
+	if override.Emoji != "" {
+		base.Emoji = override.Emoji
+	}
+
+	if override.Label != "" {
+		base.Label = override.Label
+	}
+
+	base.Failure = base.Failure || override.Failure
+
+	return base
+}
@@ -0,0 +1,147 @@
+package main_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	app "action-notify-slack"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemeStyle(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Status        string
+		Failed        bool
+		ExpectedColor string
+		ExpectedFail  bool
+	}
+
+	suite := map[string]test{
+		"Running":      {Status: "running", Failed: false, ExpectedColor: "#fbf000", ExpectedFail: false},
+		"Deploying":    {Status: "Deploying", Failed: false, ExpectedColor: "#fda100", ExpectedFail: false},
+		"Released":     {Status: "RELEASED", Failed: false, ExpectedColor: "#0ce823", ExpectedFail: false},
+		"Aborted":      {Status: "aborted", Failed: false, ExpectedColor: "#fd0000", ExpectedFail: true},
+		"Unknown":      {Status: "canary", Failed: false, ExpectedColor: "#777777", ExpectedFail: false},
+		"Success":      {Status: "success", Failed: false, ExpectedColor: "#0ce823", ExpectedFail: false},
+		"Failure":      {Status: "failure", Failed: false, ExpectedColor: "#fd0000", ExpectedFail: true},
+		"Cancelled":    {Status: "cancelled", Failed: false, ExpectedColor: "#fd0000", ExpectedFail: true},
+		"Skipped":      {Status: "skipped", Failed: false, ExpectedColor: "#777777", ExpectedFail: false},
+		"Failure Trap": {Status: "released", Failed: true, ExpectedColor: "#fd0000", ExpectedFail: true},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		style := app.DefaultTheme.Style(test.Status, test.Failed)
+
+		assert.Equal(test.ExpectedColor, style.Color)
+		assert.Equal(test.ExpectedFail, style.Failure)
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	assert := assert.New(t)
+
+	filename, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(filename.Name())
+
+	type test struct {
+		Content       string
+		Status        string
+		Failed        bool
+		ExpectedStyle app.Style
+		ExpectedError string
+	}
+
+	yaml := `
+statuses:
+  - statuses: [canary]
+    color: "#00bfff"
+    emoji: ":hatching_chick:"
+    label: Canary
+  - regex: "^smoke-.*"
+    color: "#a020f0"
+    emoji: ":dash:"
+  - statuses: [rollback]
+    color: "#8b0000"
+    emoji: ":rewind:"
+    failure: true
+default:
+  color: "#000000"
+failure:
+  emoji: ":boom:"
+`
+
+	suite := map[string]test{
+		"Keyword": {
+			Content:       yaml,
+			Status:        "Canary",
+			ExpectedStyle: app.Style{Color: "#00bfff", Emoji: ":hatching_chick:", Label: "Canary"},
+		},
+		"Regex": {
+			Content:       yaml,
+			Status:        "smoke-testing",
+			ExpectedStyle: app.Style{Color: "#a020f0", Emoji: ":dash:"},
+		},
+		"Custom Failure": {
+			Content:       yaml,
+			Status:        "rollback",
+			ExpectedStyle: app.Style{Color: "#8b0000", Emoji: ":rewind:", Failure: true},
+		},
+		"Builtin Status": {
+			Content:       yaml,
+			Status:        "running",
+			ExpectedStyle: app.Style{Color: "#fbf000", Emoji: ":yellow_square:"},
+		},
+		"Default Override": {
+			Content:       yaml,
+			Status:        "unknown",
+			ExpectedStyle: app.Style{Color: "#000000", Emoji: ":white_large_square:"},
+		},
+		"Failure Override": {
+			Content:       yaml,
+			Status:        "canary",
+			Failed:        true,
+			ExpectedStyle: app.Style{Color: "#fd0000", Emoji: ":boom:", Failure: true},
+		},
+		"JSON": {
+			Content:       `{"statuses": [{"statuses": ["canary"], "color": "#00bfff"}]}`,
+			Status:        "canary",
+			ExpectedStyle: app.Style{Color: "#00bfff"},
+		},
+		"Invalid Regex": {
+			Content:       `{"statuses": [{"regex": "(", "color": "#00bfff"}]}`,
+			ExpectedError: fmt.Sprintf("invalid regex '(' in theme file '%s': error parsing regexp: missing closing ): `(`", filename.Name()),
+		},
+		"Invalid File": {
+			Content:       `statuses: {`,
+			ExpectedError: fmt.Sprintf("invalid theme file '%s'", filename.Name()),
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		err = os.WriteFile(filename.Name(), []byte(test.Content), 0644)
+		assert.Equal(nil, err, "preparation: error writing theme to temporary file")
+
+		theme, err := app.LoadTheme(filename.Name())
+
+		if test.ExpectedError != "" {
+			assert.ErrorContains(err, test.ExpectedError)
+			continue
+		}
+
+		assert.Equal(nil, err)
+		assert.Equal(test.ExpectedStyle, theme.Style(test.Status, test.Failed))
+	}
+}
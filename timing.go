@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// ParseMessageTime returns a time a message was posted at, encoded in its timestamp
+func ParseMessageTime(ts string) (time.Time, error) {
+	parts := strings.SplitN(strings.TrimSpace(ts), ".", 2)
+
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}, errors.New(fmt.Sprintf("invalid message timestamp '%s'", ts))
+	}
+
+	var usec int64
+	if len(parts) == 2 {
+		usec, err = strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return time.Time{}, errors.New(fmt.Sprintf("invalid message timestamp '%s'", ts))
+		}
+	}
+
+	return time.Unix(sec, usec*int64(time.Microsecond)).UTC(), nil
+}
+
+// GetTimingFields returns a start time and a duration of a run, or no fields when a start time is unknown
+func GetTimingFields(startedAt, now time.Time) []slack.AttachmentField {
+	if startedAt.IsZero() {
+		return []slack.AttachmentField{}
+	}
+
+	elapsed := now.Sub(startedAt)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	return []slack.AttachmentField{
+		{
+			Title: "Started",
+			Value: fmt.Sprintf("<!date^%v^{date_short_pretty} {time}|%s>", startedAt.Unix(), startedAt.UTC().Format(time.RFC1123)),
+			Short: true,
+		},
+		{
+			Title: "Duration",
+			Value: elapsed.Round(time.Second).String(),
+			Short: true,
+		},
+	}
+}
+
+// withRunFields returns a copy of fields followed by timing fields and a status history of a run
+func (s *Slack) withRunFields(fields []slack.AttachmentField) ([]slack.AttachmentField, error) {
+	now := time.Now()
+
+	extra := GetTimingFields(s.StartedAt, now)
+
+	if s.History != nil {
+		failure, err := GetFailure()
+		if err != nil {
+			return nil, err
+		}
+
+		status, _, err := GetStatus()
+		if err != nil {
+			return nil, err
+		}
+
+		// a status being sent is recorded in a state only after it is delivered
+		history := append(s.History, StatusChange{Status: status, Failed: failure, Time: now.UTC()})
+		extra = append(extra, GetHistoryField(history))
+	}
+
+	if len(s.Stages) > 0 {
+		extra = append(extra, GetStagesField(s.Stages, now))
+	}
+
+	if len(extra) == 0 {
+		return fields, nil
+	}
+
+	// fields are shared between channels notified concurrently, hence never appended in place
+	result := make([]slack.AttachmentField, 0, len(fields)+len(extra))
+
+	return append(append(result, fields...), extra...), nil
+}
@@ -0,0 +1,100 @@
+package main_test
+
+import (
+	"testing"
+	"time"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMessageTime(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Input          string
+		ExpectedOutput time.Time
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Timestamp": {
+			Input:          "1589146397.007200",
+			ExpectedOutput: time.Date(2020, 5, 10, 21, 33, 17, 7200000, time.UTC),
+			ExpectedError:  "",
+		},
+		"Seconds": {
+			Input:          "1589146397",
+			ExpectedOutput: time.Date(2020, 5, 10, 21, 33, 17, 0, time.UTC),
+			ExpectedError:  "",
+		},
+		"Invalid": {
+			Input:          "yesterday",
+			ExpectedOutput: time.Time{},
+			ExpectedError:  "invalid message timestamp 'yesterday'",
+		},
+		"Invalid Fraction": {
+			Input:          "1589146397.abc",
+			ExpectedOutput: time.Time{},
+			ExpectedError:  "invalid message timestamp '1589146397.abc'",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		result, err := app.ParseMessageTime(test.Input)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+	}
+}
+
+func TestGetTimingFields(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2020, 5, 10, 21, 45, 47, 400000000, time.UTC)
+
+	type test struct {
+		StartedAt      time.Time
+		ExpectedOutput []slack.AttachmentField
+	}
+
+	suite := map[string]test{
+		"Unknown Start": {
+			StartedAt:      time.Time{},
+			ExpectedOutput: []slack.AttachmentField{},
+		},
+		"Elapsed": {
+			StartedAt: time.Date(2020, 5, 10, 21, 33, 17, 0, time.UTC),
+			ExpectedOutput: []slack.AttachmentField{
+				{Title: "Started", Value: "<!date^1589146397^{date_short_pretty} {time}|Sun, 10 May 2020 21:33:17 UTC>", Short: true},
+				{Title: "Duration", Value: "12m30s", Short: true},
+			},
+		},
+		"Clock Skew": {
+			StartedAt: time.Date(2020, 5, 10, 21, 46, 0, 0, time.UTC),
+			ExpectedOutput: []slack.AttachmentField{
+				{Title: "Started", Value: "<!date^1589147160^{date_short_pretty} {time}|Sun, 10 May 2020 21:46:00 UTC>", Short: true},
+				{Title: "Duration", Value: "0s", Short: true},
+			},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		assert.Equal(test.ExpectedOutput, app.GetTimingFields(test.StartedAt, now))
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// DefaultLogTail is a number of log lines posted unless configured otherwise
+const DefaultLogTail = 50
+
+// ParseGlobs returns a list of glob patterns separated by commas or new lines
+func ParseGlobs(s string) []string {
+	patterns := make([]string, 0)
+
+	for _, p := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	}) {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+
+	return patterns
+}
+
+// GlobFiles returns regular files matching any of glob patterns, ignoring patterns without matches
+func GlobFiles(patterns []string) ([]string, error) {
+	files := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, p := range patterns {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid glob pattern '%s'", p))
+		}
+
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || !info.Mode().IsRegular() || seen[m] {
+				continue
+			}
+
+			seen[m] = true
+			files = append(files, m)
+		}
+	}
+
+	return files, nil
+}
+
+// Tail returns last n lines of a file
+func Tail(filename string, n int) (string, error) {
+	file, err := os.ReadFile(filename)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("error reading file '%s'", filename))
+	}
+
+	lines := strings.Split(strings.TrimRight(string(file), "\r\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// UploadFiles uploads files into a thread of a message, skipping empty ones
+func (s *Slack) UploadFiles(cli Client, files []string, threadTS string) error {
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error reading file '%s'", f))
+		}
+
+		if info.Size() == 0 {
+			continue
+		}
+
+		err = s.upload(cli, slack.UploadFileV2Parameters{
+			File:            f,
+			FileSize:        int(info.Size()),
+			Filename:        filepath.Base(f),
+			Title:           filepath.Base(f),
+			Channel:         s.Channel,
+			ThreadTimestamp: threadTS,
+		})
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error uploading file '%s'", f))
+		}
+	}
+
+	return nil
+}
+
+// UploadLogTail uploads last lines of a log file as a snippet into a thread of a message
+func (s *Slack) UploadLogTail(cli Client, filename string, lines int, threadTS string) error {
+	content, err := Tail(filename, lines)
+	if err != nil {
+		return err
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	err = s.upload(cli, slack.UploadFileV2Parameters{
+		Content:         content,
+		FileSize:        len(content),
+		Filename:        filepath.Base(filename),
+		Title:           fmt.Sprintf("Last %v lines of %s", lines, filepath.Base(filename)),
+		Channel:         s.Channel,
+		ThreadTimestamp: threadTS,
+	})
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error uploading log file '%s'", filename))
+	}
+
+	return nil
+}
+
+func (s *Slack) upload(cli Client, params slack.UploadFileV2Parameters) error {
+	return s.Retry.Do(s.Context, func() error {
+		_, err := cli.UploadFileV2Context(s.Context, params)
+		return err
+	})
+}
@@ -0,0 +1,199 @@
+package main_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	app "action-notify-slack"
+
+	"action-notify-slack/mocks"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGlobFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary directory")
+	defer os.RemoveAll(dir)
+
+	for _, f := range []string{"a.xml", "b.xml", "c.txt"} {
+		err = os.WriteFile(filepath.Join(dir, f), []byte("content"), 0644)
+		assert.Equal(nil, err, "preparation: error writing temporary file")
+	}
+
+	err = os.Mkdir(filepath.Join(dir, "d.xml"), 0755)
+	assert.Equal(nil, err, "preparation: error creating temporary directory")
+
+	type test struct {
+		Patterns       []string
+		ExpectedOutput []string
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Glob": {
+			Patterns:       []string{filepath.Join(dir, "*.xml")},
+			ExpectedOutput: []string{filepath.Join(dir, "a.xml"), filepath.Join(dir, "b.xml")},
+			ExpectedError:  "",
+		},
+		"Duplicates": {
+			Patterns:       []string{filepath.Join(dir, "a.xml"), filepath.Join(dir, "*.xml"), filepath.Join(dir, "c.txt")},
+			ExpectedOutput: []string{filepath.Join(dir, "a.xml"), filepath.Join(dir, "b.xml"), filepath.Join(dir, "c.txt")},
+			ExpectedError:  "",
+		},
+		"No Matches": {
+			Patterns:       []string{filepath.Join(dir, "*.json")},
+			ExpectedOutput: []string{},
+			ExpectedError:  "",
+		},
+		"Invalid Pattern": {
+			Patterns:       []string{"["},
+			ExpectedOutput: nil,
+			ExpectedError:  "invalid glob pattern '[': syntax error in pattern",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		result, err := app.GlobFiles(test.Patterns)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+	}
+}
+
+func TestTail(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(file.Name())
+
+	err = os.WriteFile(file.Name(), []byte("line-1\nline-2\nline-3\n"), 0644)
+	assert.Equal(nil, err, "preparation: error writing temporary file")
+
+	type test struct {
+		Lines          int
+		ExpectedOutput string
+	}
+
+	suite := map[string]test{
+		"Last Lines": {Lines: 2, ExpectedOutput: "line-2\nline-3"},
+		"Whole File": {Lines: 10, ExpectedOutput: "line-1\nline-2\nline-3"},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		result, err := app.Tail(file.Name(), test.Lines)
+		assert.Equal(nil, err)
+		assert.Equal(test.ExpectedOutput, result)
+	}
+
+	_, err = app.Tail(file.Name()+"-missing", 1)
+	assert.ErrorContains(err, fmt.Sprintf("error reading file '%s-missing'", file.Name()))
+}
+
+func TestUploadFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary directory")
+	defer os.RemoveAll(dir)
+
+	report := filepath.Join(dir, "report.xml")
+	err = os.WriteFile(report, []byte("<testsuite/>"), 0644)
+	assert.Equal(nil, err, "preparation: error writing temporary file")
+
+	empty := filepath.Join(dir, "empty.xml")
+	err = os.WriteFile(empty, []byte{}, 0644)
+	assert.Equal(nil, err, "preparation: error writing temporary file")
+
+	type test struct {
+		MockError     error
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Upload": {
+			MockError:     nil,
+			ExpectedError: "",
+		},
+		"slack.UploadFileV2Context Error": {
+			MockError:     errors.New("reason"),
+			ExpectedError: fmt.Sprintf("error uploading file '%s': reason", report),
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		s := &app.Slack{Channel: "self", Context: context.Background(), Retry: app.RetryPolicy{Attempts: 1}}
+
+		m := new(mocks.Client)
+		m.On("UploadFileV2Context", s.Context, slack.UploadFileV2Parameters{
+			File:            report,
+			FileSize:        12,
+			Filename:        "report.xml",
+			Title:           "report.xml",
+			Channel:         "self",
+			ThreadTimestamp: "1589146397.007200",
+		}).Return(&slack.FileSummary{ID: "F123"}, test.MockError)
+
+		err := s.UploadFiles(m, []string{empty, report}, "1589146397.007200")
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		m.AssertNumberOfCalls(t, "UploadFileV2Context", 1)
+	}
+}
+
+func TestUploadLogTail(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(file.Name())
+
+	err = os.WriteFile(file.Name(), []byte("ok\nFAIL: TestSomething\nexit status 1\n"), 0644)
+	assert.Equal(nil, err, "preparation: error writing temporary file")
+
+	s := &app.Slack{Channel: "self", Context: context.Background(), Retry: app.RetryPolicy{Attempts: 1}}
+
+	m := new(mocks.Client)
+	m.On("UploadFileV2Context", s.Context, mock.MatchedBy(func(p slack.UploadFileV2Parameters) bool {
+		return p.Content == "FAIL: TestSomething\nexit status 1" &&
+			p.FileSize == len(p.Content) &&
+			p.Title == fmt.Sprintf("Last 2 lines of %s", filepath.Base(file.Name())) &&
+			p.ThreadTimestamp == "1589146397.007200"
+	})).Return(&slack.FileSummary{ID: "F123"}, nil)
+
+	err = s.UploadLogTail(m, file.Name(), 2, "1589146397.007200")
+	assert.Equal(nil, err)
+
+	m.AssertExpectations(t)
+}
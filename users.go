@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Users maps GitHub logins to Slack user IDs
+type Users map[string]string
+
+// LoadUsers returns a mapping of GitHub logins to Slack user IDs read from a YAML or JSON file
+func LoadUsers(filename string) (Users, error) {
+	file, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error reading file '%s'", filename))
+	}
+
+	raw := make(map[string]string)
+	if err := yaml.Unmarshal(file, &raw); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("invalid users file '%s'", filename))
+	}
+
+	// GitHub logins are case insensitive
+	users := make(Users)
+	for login, id := range raw {
+		users[strings.ToLower(login)] = strings.TrimSpace(id)
+	}
+
+	return users, nil
+}
+
+// Get returns a Slack user ID of a GitHub login, or an empty string when not mapped
+func (u Users) Get(login string) string {
+	return u[strings.ToLower(login)]
+}
+
+// GetSlackUserID returns a Slack user ID of a GitHub login from a mapping, or optionally
+// by looking up an email of a commit author, as long as the author is the same user
+func (s *Slack) GetSlackUserID(cli Client, users Users, login string, commit *Commit, lookup bool) (string, error) {
+	if id := users.Get(login); id != "" {
+		return id, nil
+	}
+
+	if !lookup || commit == nil || commit.Author.Email == "" {
+		return "", nil
+	}
+
+	if commit.Author.Username != "" && !strings.EqualFold(commit.Author.Username, login) {
+		return "", nil
+	}
+
+	var id string
+	err := s.Retry.Do(s.Context, func() error {
+		u, err := cli.GetUserByEmailContext(s.Context, commit.Author.Email)
+		if err != nil {
+			return err
+		}
+
+		id = u.ID
+		return nil
+	})
+
+	if IsSlackError(err, "users_not_found") {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("error looking up Slack user of '%s'", login))
+	}
+
+	return id, nil
+}
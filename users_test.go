@@ -0,0 +1,160 @@
+package main_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	app "action-notify-slack"
+
+	"action-notify-slack/mocks"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadUsers(t *testing.T) {
+	assert := assert.New(t)
+
+	filename, err := os.CreateTemp(os.TempDir(), "test-")
+	assert.Equal(nil, err, "preparation: error creating temporary file")
+	defer os.Remove(filename.Name())
+
+	type test struct {
+		Content        string
+		ExpectedOutput app.Users
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"YAML": {
+			Content:        "octocat: U123\nMonaLisa: U456\n",
+			ExpectedOutput: app.Users{"octocat": "U123", "monalisa": "U456"},
+			ExpectedError:  "",
+		},
+		"JSON": {
+			Content:        `{"octocat": "U123"}`,
+			ExpectedOutput: app.Users{"octocat": "U123"},
+			ExpectedError:  "",
+		},
+		"Invalid": {
+			Content:        "- octocat\n",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Sprintf("invalid users file '%s'", filename.Name()),
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		err = os.WriteFile(filename.Name(), []byte(test.Content), 0644)
+		assert.Equal(nil, err, "preparation: error writing users file")
+
+		result, err := app.LoadUsers(filename.Name())
+
+		if test.ExpectedError != "" {
+			assert.ErrorContains(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+	}
+}
+
+func TestGetSlackUserID(t *testing.T) {
+	assert := assert.New(t)
+
+	commit := func(username, email string) *app.Commit {
+		c := new(app.Commit)
+		c.Author.Username = username
+		c.Author.Email = email
+		return c
+	}
+
+	type test struct {
+		Login          string
+		Commit         *app.Commit
+		Lookup         bool
+		MockUser       *slack.User
+		MockError      error
+		ExpectedOutput string
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Mapped": {
+			Login:          "OctoCat",
+			Commit:         nil,
+			Lookup:         false,
+			ExpectedOutput: "U123",
+			ExpectedError:  "",
+		},
+		"Not Mapped": {
+			Login:          "monalisa",
+			Commit:         commit("monalisa", "monalisa@example.com"),
+			Lookup:         false,
+			ExpectedOutput: "",
+			ExpectedError:  "",
+		},
+		"Lookup by Email": {
+			Login:          "monalisa",
+			Commit:         commit("monalisa", "monalisa@example.com"),
+			Lookup:         true,
+			MockUser:       &slack.User{ID: "U456"},
+			ExpectedOutput: "U456",
+			ExpectedError:  "",
+		},
+		"Commit of Another Author": {
+			Login:          "monalisa",
+			Commit:         commit("octocat", "octocat@example.com"),
+			Lookup:         true,
+			MockUser:       &slack.User{ID: "U123"},
+			ExpectedOutput: "",
+			ExpectedError:  "",
+		},
+		"User Not Found": {
+			Login:          "monalisa",
+			Commit:         commit("monalisa", "monalisa@example.com"),
+			Lookup:         true,
+			MockError:      slack.SlackErrorResponse{Err: "users_not_found"},
+			ExpectedOutput: "",
+			ExpectedError:  "",
+		},
+		"slack.GetUserByEmailContext Error": {
+			Login:          "monalisa",
+			Commit:         commit("monalisa", "monalisa@example.com"),
+			Lookup:         true,
+			MockError:      errors.New("reason"),
+			ExpectedOutput: "",
+			ExpectedError:  "error looking up Slack user of 'monalisa': reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		s := &app.Slack{Context: context.Background()}
+
+		m := new(mocks.Client)
+		if test.Commit != nil {
+			m.On("GetUserByEmailContext", s.Context, test.Commit.Author.Email).Return(test.MockUser, test.MockError)
+		}
+
+		result, err := s.GetSlackUserID(m, app.Users{"octocat": "U123"}, test.Login, test.Commit, test.Lookup)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+		} else {
+			assert.Equal(nil, err)
+		}
+
+		assert.Equal(test.ExpectedOutput, result)
+	}
+}
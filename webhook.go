@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// Webhook represents Slack Incoming Webhook transport, implementing a subset of Client functionality
+type Webhook struct {
+	URL string
+}
+
+// ErrWebhookUnsupported returns an error of a feature that is not available via Incoming Webhooks
+func ErrWebhookUnsupported(feature string) error {
+	return errors.New(fmt.Sprintf("%s is not supported with 'WEBHOOK_URL', use 'TOKEN' instead", feature))
+}
+
+// PostMessageContext posts a message via webhook. Webhooks do not return message timestamps, hence it is always empty
+func (w *Webhook) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+	msg, err := NewWebhookMessage(channelID, options...)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := slack.PostWebhookContext(ctx, w.URL, msg); err != nil {
+		return "", "", err
+	}
+
+	return channelID, "", nil
+}
+
+// UpdateMessageContext is not supported by webhooks
+func (w *Webhook) UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	return "", "", "", ErrWebhookUnsupported("updating messages")
+}
+
+// DeleteMessageContext is not supported by webhooks
+func (w *Webhook) DeleteMessageContext(ctx context.Context, channel, messageTimestamp string) (string, string, error) {
+	return "", "", ErrWebhookUnsupported("deleting messages")
+}
+
+// ScheduleMessageContext is not supported by webhooks
+func (w *Webhook) ScheduleMessageContext(ctx context.Context, channelID, postAt string, options ...slack.MsgOption) (string, string, error) {
+	return "", "", ErrWebhookUnsupported("scheduling messages")
+}
+
+// DeleteScheduledMessageContext is not supported by webhooks
+func (w *Webhook) DeleteScheduledMessageContext(ctx context.Context, params *slack.DeleteScheduledMessageParameters) (bool, error) {
+	return false, ErrWebhookUnsupported("deleting scheduled messages")
+}
+
+// PostEphemeralContext is not supported by webhooks
+func (w *Webhook) PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error) {
+	return "", ErrWebhookUnsupported("sending ephemeral messages")
+}
+
+// GetUserByEmailContext is not supported by webhooks
+func (w *Webhook) GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error) {
+	return nil, ErrWebhookUnsupported("looking up users by email")
+}
+
+// GetConversationsContext is not supported by webhooks
+func (w *Webhook) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	return nil, "", ErrWebhookUnsupported("resolving channel names")
+}
+
+// GetConversationHistoryContext is not supported by webhooks
+func (w *Webhook) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	return nil, ErrWebhookUnsupported("finding messages of a run")
+}
+
+// GetPermalinkContext is not supported by webhooks
+func (w *Webhook) GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error) {
+	return "", ErrWebhookUnsupported("retrieving permalinks")
+}
+
+// UploadFileV2Context is not supported by webhooks
+func (w *Webhook) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	return nil, ErrWebhookUnsupported("uploading files")
+}
+
+// NewWebhookMessage converts message options into a webhook payload
+func NewWebhookMessage(channelID string, options ...slack.MsgOption) (*slack.WebhookMessage, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error applying message options")
+	}
+
+	msg := &slack.WebhookMessage{
+		Channel:         values.Get("channel"),
+		Text:            values.Get("text"),
+		ThreadTimestamp: values.Get("thread_ts"),
+		Username:        values.Get("username"),
+		IconEmoji:       values.Get("icon_emoji"),
+		IconURL:         values.Get("icon_url"),
+	}
+
+	if v := values.Get("reply_broadcast"); v != "" {
+		msg.ReplyBroadcast, _ = strconv.ParseBool(v)
+	}
+
+	if v := values.Get("attachments"); v != "" {
+		if err := json.Unmarshal([]byte(v), &msg.Attachments); err != nil {
+			return nil, errors.Wrap(err, "error decoding attachments")
+		}
+	}
+
+	if v := values.Get("blocks"); v != "" {
+		msg.Blocks = new(slack.Blocks)
+		if err := json.Unmarshal([]byte(v), msg.Blocks); err != nil {
+			return nil, errors.Wrap(err, "error decoding blocks")
+		}
+	}
+
+	return msg, nil
+}
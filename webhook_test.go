@@ -0,0 +1,114 @@
+package main_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	app "action-notify-slack"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSendTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	type test struct {
+		Receiver      *app.Slack
+		StatusCode    int
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Attachment": {
+			Receiver: &app.Slack{
+				Channel: "",
+				Context: context.Background(),
+			},
+			StatusCode:    http.StatusOK,
+			ExpectedError: "",
+		},
+		"Block Kit": {
+			Receiver: &app.Slack{
+				Channel:  "",
+				Context:  context.Background(),
+				BlockKit: true,
+			},
+			StatusCode:    http.StatusOK,
+			ExpectedError: "",
+		},
+		"Update": {
+			Receiver: &app.Slack{
+				Channel:   "",
+				Context:   context.Background(),
+				Timestamp: "1589146397.007200",
+			},
+			StatusCode:    http.StatusOK,
+			ExpectedError: "error updating message: updating messages is not supported with 'WEBHOOK_URL', use 'TOKEN' instead",
+		},
+		"Server Error": {
+			Receiver: &app.Slack{
+				Channel: "",
+				Context: context.Background(),
+			},
+			StatusCode:    http.StatusNotFound,
+			ExpectedError: "error sending message: slack server error: 404 Not Found",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		var received slack.WebhookMessage
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &received)
+			w.WriteHeader(test.StatusCode)
+		}))
+
+		result, err := test.Receiver.SendTemplate(&app.Webhook{URL: server.URL}, []slack.AttachmentField{})
+		server.Close()
+
+		assert.Equal("", result)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		assert.Equal(nil, err)
+
+		if test.Receiver.BlockKit {
+			assert.NotEmpty(received.Blocks.BlockSet)
+			assert.NotEmpty(received.Text)
+		} else {
+			assert.Equal(1, len(received.Attachments))
+			assert.Equal(4, len(received.Attachments[0].Fields))
+		}
+	}
+}
+
+func TestNewWebhookMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	msg, err := app.NewWebhookMessage(
+		"C0001",
+		slack.MsgOptionText("text", false),
+		slack.MsgOptionTS("1589146397.007200"),
+		slack.MsgOptionBroadcast(),
+		slack.MsgOptionAttachments(slack.Attachment{Color: "#0ce823"}),
+	)
+
+	assert.Equal(nil, err)
+	assert.Equal("C0001", msg.Channel)
+	assert.Equal("text", msg.Text)
+	assert.Equal("1589146397.007200", msg.ThreadTimestamp)
+	assert.Equal(true, msg.ReplyBroadcast)
+	assert.Equal([]slack.Attachment{{Color: "#0ce823"}}, msg.Attachments)
+}